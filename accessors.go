@@ -0,0 +1,125 @@
+package options
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterInt returns the values of the filter conditions for the field
+// and operator as integers, or nil when there are none
+func (o Options) FilterInt(field string, op Operator) ([]int, error) {
+	return filterValues(o, field, op, "value must be an integer", strconv.Atoi)
+}
+
+// FilterBool returns the values of the filter conditions for the field
+// and operator as booleans, or nil when there are none
+func (o Options) FilterBool(field string, op Operator) ([]bool, error) {
+	return filterValues(o, field, op, "value must be true or false", strconv.ParseBool)
+}
+
+// FilterTime returns the values of the filter conditions for the field
+// and operator as times, provided as RFC 3339 times or dates (i.e.
+// 2006-01-02), or nil when there are none
+func (o Options) FilterTime(field string, op Operator) ([]time.Time, error) {
+	return filterValues(o, field, op, "value must be an RFC 3339 time or date", func(s string) (time.Time, error) {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return time.Parse(time.DateOnly, s)
+		}
+
+		return t, nil
+	})
+}
+
+// FilterDuration returns the values of the filter conditions for the
+// field and operator as durations (i.e. 1h30m), or nil when there are none
+func (o Options) FilterDuration(field string, op Operator) ([]time.Duration, error) {
+	return filterValues(o, field, op, "value must be a duration (i.e. 1h30m)", time.ParseDuration)
+}
+
+// FilterUUID returns the values of the filter conditions for the field
+// and operator as UUIDs, or nil when there are none
+func (o Options) FilterUUID(field string, op Operator) ([]UUID, error) {
+	return filterValues(o, field, op, "value must be a UUID", ParseUUID)
+}
+
+// FilterEnum returns the values of the filter conditions for the field
+// and operator, confirming that each is one of the allowed values, or nil
+// when there are none
+func (o Options) FilterEnum(field string, op Operator, allowed ...string) ([]string, error) {
+	detail := fmt.Sprintf("value must be one of %s", strings.Join(allowed, ", "))
+
+	return filterValues(o, field, op, detail, func(s string) (string, error) {
+		for _, a := range allowed {
+			if s == a {
+				return s, nil
+			}
+		}
+
+		return "", fmt.Errorf("%q is not permitted", s)
+	})
+}
+
+// PageInt returns the page value for the key (i.e. limit), or the
+// provided default when it is not present
+func (o Options) PageInt(key string, def int) int {
+	if v, ok := o.Page[key]; ok {
+		return v
+	}
+
+	return def
+}
+
+// filterValues parses the values of the filter conditions for the field
+// and operator, returning a ParseError naming the parameter when a value
+// is invalid
+func filterValues[T any](o Options, field string, op Operator, detail string, parse func(string) (T, error)) ([]T, error) {
+	var values []T
+
+	for _, fc := range o.Conditions() {
+		if fc.Field != field || !matchesOperator(fc.Operator, op) {
+			continue
+		}
+
+		for _, s := range fc.Values {
+			v, err := parse(s)
+			if err != nil {
+				return nil, &ParseError{
+					Code:      ErrCodeInvalidValue,
+					Parameter: conditionParameter(fc),
+					Value:     s,
+					Detail:    detail,
+					Err:       err,
+				}
+			}
+
+			values = append(values, v)
+		}
+	}
+
+	return values, nil
+}
+
+// matchesOperator reports whether a condition with the operator is
+// requested by op, eq and in both match values that must equal one of
+// the values provided (i.e. filter[status]=open and
+// filter[status]=open,closed)
+func matchesOperator(operator, op Operator) bool {
+	if op == OpEq || op == OpIn {
+		return operator == OpEq || operator == OpIn
+	}
+
+	return operator == op
+}
+
+// conditionParameter returns the querystring parameter of the condition
+// (i.e. filter[age][gte])
+func conditionParameter(fc FilterCondition) string {
+	if fc.Operator == OpEq || fc.Operator == OpIn {
+		return fmt.Sprintf("filter[%s]", fc.Field)
+	}
+
+	return fmt.Sprintf("filter[%s][%s]", fc.Field, fc.Operator)
+}
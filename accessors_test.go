@@ -0,0 +1,162 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOptions_FilterInt(t *testing.T) {
+	tests := []struct {
+		name      string
+		qs        string
+		op        Operator
+		want      []int
+		wantParam string
+	}{
+		{"not filtered", "filter[name]=x", OpEq, nil, ""},
+		{"single value", "filter[age]=21", OpEq, []int{21}, ""},
+		{"multiple values", "filter[age]=21,34", OpEq, []int{21, 34}, ""},
+		{"in matches eq", "filter[age]=21", OpIn, []int{21}, ""},
+		{"lower bound", "filter[age][gte]=21&filter[age][lt]=65", OpGte, []int{21}, ""},
+		{"upper bound", "filter[age][gte]=21&filter[age][lt]=65", OpLt, []int{65}, ""},
+		{"other operator", "filter[age][gte]=21", OpEq, nil, ""},
+		{"comparison prefix", "filter[age]=>=21", OpGte, []int{21}, ""},
+		{"invalid value", "filter[age]=21,old", OpIn, nil, "filter[age]"},
+		{"invalid operator value", "filter[age][gte]=old", OpGte, nil, "filter[age][gte]"},
+		{"invalid value of another operator", "filter[age][gte]=old&filter[age]=21", OpEq, []int{21}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := o.FilterInt("age", tt.op)
+			if tt.wantParam != "" {
+				pe, ok := err.(*ParseError)
+				if !ok || pe.Code != ErrCodeInvalidValue || pe.Parameter != tt.wantParam {
+					t.Errorf("Options.FilterInt() error = %v, want invalid value of %s", err, tt.wantParam)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Options.FilterInt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions_filterAccessors(t *testing.T) {
+	o, err := FromQuerystring("filter[active]=true" +
+		"&filter[created][gte]=2021-01-01&filter[created][lt]=2021-06-01T12:00:00Z" +
+		"&filter[timeout]=1h30m" +
+		"&filter[id]=123e4567-e89b-12d3-a456-426614174000,123E4567E89B12D3A456426614174001" +
+		"&filter[status]=open,closed" +
+		"&filter[bad]=maybe")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := o.FilterBool("active", OpEq); err != nil || !reflect.DeepEqual(got, []bool{true}) {
+		t.Errorf("Options.FilterBool() = %v, %v", got, err)
+	}
+
+	if _, err := o.FilterBool("bad", OpEq); err == nil {
+		t.Error("Options.FilterBool() expected an error")
+	}
+
+	since := []time.Time{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if got, err := o.FilterTime("created", OpGte); err != nil || !reflect.DeepEqual(got, since) {
+		t.Errorf("Options.FilterTime() = %v, %v, want %v", got, err, since)
+	}
+
+	until := []time.Time{time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)}
+	if got, err := o.FilterTime("created", OpLt); err != nil || !reflect.DeepEqual(got, until) {
+		t.Errorf("Options.FilterTime() = %v, %v, want %v", got, err, until)
+	}
+
+	if _, err := o.FilterTime("bad", OpEq); err == nil {
+		t.Error("Options.FilterTime() expected an error")
+	}
+
+	if got, err := o.FilterDuration("timeout", OpEq); err != nil || !reflect.DeepEqual(got, []time.Duration{90 * time.Minute}) {
+		t.Errorf("Options.FilterDuration() = %v, %v", got, err)
+	}
+
+	if _, err := o.FilterDuration("bad", OpEq); err == nil {
+		t.Error("Options.FilterDuration() expected an error")
+	}
+
+	ids, err := o.FilterUUID("id", OpIn)
+	if err != nil || len(ids) != 2 || ids[0].String() != "123e4567-e89b-12d3-a456-426614174000" || ids[1].String() != "123e4567-e89b-12d3-a456-426614174001" {
+		t.Errorf("Options.FilterUUID() = %v, %v", ids, err)
+	}
+
+	if _, err := o.FilterUUID("bad", OpEq); err == nil {
+		t.Error("Options.FilterUUID() expected an error")
+	}
+
+	if got, err := o.FilterEnum("status", OpIn, "open", "closed", "pending"); err != nil || !reflect.DeepEqual(got, []string{"open", "closed"}) {
+		t.Errorf("Options.FilterEnum() = %v, %v", got, err)
+	}
+
+	_, err = o.FilterEnum("status", OpIn, "open")
+	if pe, ok := err.(*ParseError); !ok || pe.Parameter != "filter[status]" || pe.Value != "closed" || pe.Detail != "value must be one of open" {
+		t.Errorf("Options.FilterEnum() error = %#v", err)
+	}
+}
+
+func TestOptions_PageInt(t *testing.T) {
+	o, err := FromQuerystring("page[limit]=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := o.PageInt("limit", 100); got != 10 {
+		t.Errorf("Options.PageInt() = %v, want 10", got)
+	}
+
+	if got := o.PageInt("offset", 0); got != 0 {
+		t.Errorf("Options.PageInt() = %v, want 0", got)
+	}
+
+	if got := (Options{}).PageInt("limit", 100); got != 100 {
+		t.Errorf("Options.PageInt() = %v, want 100", got)
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{"canonical", "123e4567-e89b-12d3-a456-426614174000", "123e4567-e89b-12d3-a456-426614174000", false},
+		{"uppercase", "123E4567-E89B-12D3-A456-426614174000", "123e4567-e89b-12d3-a456-426614174000", false},
+		{"without hyphens", "123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-426614174000", false},
+		{"misplaced hyphens", "123e4567e-89b-12d3-a456-42661417400", "", true},
+		{"invalid characters", "123e4567-e89b-12d3-a456-42661417400z", "", true},
+		{"too short", "123e4567", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUUID(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUUID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("ParseUUID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package options
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bind fills the fields of the struct referenced by dst from the Options
+// according to the query tag of each field, which contains the kind of
+// parameter, the name and any options:
+//
+//	Status  string    `query:"filter,status"`            // filter[status]
+//	MinAge  *int      `query:"filter,age,gte"`           // filter[age][gte]
+//	IDs     []UUID    `query:"filter,id"`                // filter[id]=a,b
+//	Limit   int       `query:"page,limit,default=25,max=100"`
+//	After   string    `query:"page,after"`               // page[after]
+//	Sort    []string  `query:"sort,default=-created"`
+//	Fields  []string  `query:"fields"`                   // fields
+//	People  []string  `query:"fields,people"`            // fields[people]
+//	Include []string  `query:"include"`
+//
+// Values are converted to the type of the field (strings, numbers,
+// booleans, time.Time, time.Duration and UUID, or pointers and slices
+// of these), default provides a value when the parameter is not present
+// (multiple values are separated by |, i.e. default=-created|name) and
+// min and max limit numeric values. A ParseError is reported for each
+// parameter that cannot be bound.
+func Bind(o Options, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: dst must be a non-nil pointer to a struct")
+	}
+
+	var errs ParseErrors
+	if err := bindStruct(o, v.Elem(), &errs); err != nil {
+		return err
+	}
+
+	return errs.errs()
+}
+
+// BindQuerystring parses an Options object from the provided querystring
+// and binds it to dst
+func BindQuerystring(qs string, dst any) (Options, error) {
+	o, err := FromQuerystring(qs)
+	if err != nil {
+		return o, err
+	}
+
+	return o, Bind(o, dst)
+}
+
+// bindTag is the parsed query tag of a struct field
+type bindTag struct {
+	kind     string
+	name     string
+	operator Operator
+	def      *string
+	min      *float64
+	max      *float64
+}
+
+func parseBindTag(tag string) (bindTag, error) {
+	parts := strings.Split(tag, ",")
+	bt := bindTag{kind: parts[0]}
+
+	for i, part := range parts[1:] {
+		key, value, ok := strings.Cut(part, "=")
+
+		switch {
+		case ok && key == "default":
+			bt.def = &value
+		case ok && (key == "min" || key == "max"):
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return bt, fmt.Errorf("bind: invalid %s in tag %q", key, tag)
+			}
+
+			if key == "min" {
+				bt.min = &f
+			} else {
+				bt.max = &f
+			}
+		case ok:
+			return bt, fmt.Errorf("bind: unsupported option %q in tag %q", key, tag)
+		case i == 0:
+			bt.name = part
+		case i == 1 && bt.kind == "filter":
+			op, err := ParseOperator(part)
+			if err != nil {
+				return bt, fmt.Errorf("bind: %w in tag %q", err, tag)
+			}
+
+			bt.operator = op
+		default:
+			return bt, fmt.Errorf("bind: unexpected %q in tag %q", part, tag)
+		}
+	}
+
+	switch bt.kind {
+	case "filter", "page":
+		if bt.name == "" {
+			return bt, fmt.Errorf("bind: a name is required in tag %q", tag)
+		}
+	case "fields", "include", "sort":
+	default:
+		return bt, fmt.Errorf("bind: unsupported parameter %q in tag %q", bt.kind, tag)
+	}
+
+	return bt, nil
+}
+
+// parameter returns the querystring parameter described by the tag
+func (bt bindTag) parameter() string {
+	switch {
+	case bt.kind == "filter" && bt.operator != "":
+		return fmt.Sprintf("filter[%s][%s]", bt.name, bt.operator)
+	case bt.name != "":
+		return fmt.Sprintf("%s[%s]", bt.kind, bt.name)
+	}
+
+	return bt.kind
+}
+
+// values returns the values of the parameter described by the tag, along
+// with whether or not the parameter was provided
+func (bt bindTag) values(o Options) ([]string, bool) {
+	switch bt.kind {
+	case "filter":
+		var values []string
+		found := false
+
+		for _, fc := range o.Conditions() {
+			if fc.Field != bt.name {
+				continue
+			}
+
+			// without an operator, equality and membership are bound
+			if fc.Operator == bt.operator || (bt.operator == "" && (fc.Operator == OpEq || fc.Operator == OpIn)) {
+				values = append(values, fc.Values...)
+				found = true
+			}
+		}
+
+		return values, found
+	case "page":
+		if bt.name == "after" || bt.name == "before" {
+			c, ok := o.Cursor[bt.name]
+			return []string{c}, ok
+		}
+
+		p, ok := o.Page[bt.name]
+		return []string{strconv.Itoa(p)}, ok
+	case "fields":
+		if bt.name != "" {
+			fields, ok := o.FieldSets[bt.name]
+			return fields, ok
+		}
+
+		return o.Fields, len(o.Fields) > 0
+	case "include":
+		paths := o.IncludePaths()
+		return paths, len(paths) > 0
+	case "sort":
+		return o.Sort, len(o.Sort) > 0
+	}
+
+	return nil, false
+}
+
+func bindStruct(o Options, v reflect.Value, errs *ParseErrors) error {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		fv := v.Field(i)
+
+		tag, ok := sf.Tag.Lookup("query")
+		if !ok {
+			// embedded structs may contain tagged fields
+			if sf.Anonymous && fv.Kind() == reflect.Struct {
+				if err := bindStruct(o, fv, errs); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		bt, err := parseBindTag(tag)
+		if err != nil {
+			return err
+		}
+
+		values, ok := bt.values(o)
+		if !ok {
+			if bt.def == nil {
+				continue
+			}
+
+			values = strings.Split(*bt.def, "|")
+		}
+
+		if pe := bindField(fv, bt, values); pe != nil {
+			*errs = append(*errs, pe)
+		}
+	}
+
+	return nil
+}
+
+// bindField converts the values to the type of the field
+func bindField(fv reflect.Value, bt bindTag, values []string) *ParseError {
+	newError := func(value string, detail string, err error) *ParseError {
+		return &ParseError{
+			Code:      ErrCodeInvalidValue,
+			Parameter: bt.parameter(),
+			Value:     value,
+			Detail:    detail,
+			Err:       err,
+		}
+	}
+
+	if fv.Kind() == reflect.Slice {
+		s := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, value := range values {
+			if pe := bindValue(s.Index(i), bt, value, newError); pe != nil {
+				return pe
+			}
+		}
+
+		fv.Set(s)
+		return nil
+	}
+
+	// a single value is expected, although string fields accept a list
+	if len(values) != 1 {
+		if fv.Kind() == reflect.String {
+			fv.SetString(strings.Join(values, ","))
+			return nil
+		}
+
+		return newError(strings.Join(values, ","), "a single value must be provided", nil)
+	}
+
+	return bindValue(fv, bt, values[0], newError)
+}
+
+func bindValue(fv reflect.Value, bt bindTag, value string, newError func(string, string, error) *ParseError) *ParseError {
+	if fv.Kind() == reflect.Pointer {
+		p := reflect.New(fv.Type().Elem())
+		if pe := bindValue(p.Elem(), bt, value, newError); pe != nil {
+			return pe
+		}
+
+		fv.Set(p)
+		return nil
+	}
+
+	switch fv.Type() {
+	case reflect.TypeOf(time.Time{}):
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			if t, err = time.Parse(time.DateOnly, value); err != nil {
+				return newError(value, "value must be an RFC 3339 time or date", err)
+			}
+		}
+
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	case reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return newError(value, "value must be a duration (i.e. 1h30m)", err)
+		}
+
+		fv.SetInt(int64(d))
+		return nil
+	case reflect.TypeOf(UUID{}):
+		u, err := ParseUUID(value)
+		if err != nil {
+			return newError(value, "value must be a UUID", err)
+		}
+
+		fv.Set(reflect.ValueOf(u))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return newError(value, "value must be true or false", err)
+		}
+
+		fv.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return newError(value, "value must be an integer", err)
+		}
+
+		if pe := bindLimits(bt, float64(i), value, newError); pe != nil {
+			return pe
+		}
+
+		fv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return newError(value, "value must be a non-negative integer", err)
+		}
+
+		if pe := bindLimits(bt, float64(u), value, newError); pe != nil {
+			return pe
+		}
+
+		fv.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return newError(value, "value must be a number", err)
+		}
+
+		if pe := bindLimits(bt, f, value, newError); pe != nil {
+			return pe
+		}
+
+		fv.SetFloat(f)
+		return nil
+	default:
+		return newError(value, fmt.Sprintf("unable to bind to %s", fv.Type()), nil)
+	}
+}
+
+// bindLimits confirms that the numeric value is within the min and max of
+// the tag, it is checked before the field is set so that an invalid value
+// does not replace the current value of the field
+func bindLimits(bt bindTag, n float64, value string, newError func(string, string, error) *ParseError) *ParseError {
+	if bt.min != nil && n < *bt.min {
+		return newError(value, fmt.Sprintf("value may not be less than %v", *bt.min), nil)
+	}
+
+	if bt.max != nil && n > *bt.max {
+		pe := newError(value, fmt.Sprintf("value may not exceed %v", *bt.max), nil)
+		if bt.kind == "page" {
+			pe.Code = ErrCodeMaxSizeExceeded
+		}
+
+		return pe
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package options
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type bindPaging struct {
+	Limit  int    `query:"page,limit,default=25,max=100"`
+	Offset int    `query:"page,offset,min=0"`
+	After  string `query:"page,after"`
+}
+
+type bindQuery struct {
+	bindPaging
+
+	Status  string        `query:"filter,status"`
+	MinAge  *int          `query:"filter,age,gte"`
+	MaxAge  *int          `query:"filter,age,lt"`
+	Active  *bool         `query:"filter,active"`
+	Since   time.Time     `query:"filter,created,gte"`
+	Timeout time.Duration `query:"filter,timeout"`
+	IDs     []UUID        `query:"filter,id"`
+	Tags    []string      `query:"filter,tag,default=a|b"`
+	Score   float64       `query:"filter,score,min=0,max=10"`
+	Sort    []string      `query:"sort,default=-created"`
+	Fields  []string      `query:"fields"`
+	People  []string      `query:"fields,people"`
+	Include []string      `query:"include"`
+	Ignored string        `query:"-"`
+	Untaged string
+}
+
+func TestBind(t *testing.T) {
+	minAge := 21
+	active := true
+
+	tests := []struct {
+		name string
+		qs   string
+		want bindQuery
+	}{
+		{
+			"defaults",
+			"",
+			bindQuery{
+				bindPaging: bindPaging{Limit: 25},
+				Tags:       []string{"a", "b"},
+				Sort:       []string{"-created"},
+			},
+		},
+		{
+			"all parameters",
+			"filter[status]=open&filter[age][gte]=21&filter[active]=true&filter[created][gte]=2021-01-01" +
+				"&filter[timeout]=1m&filter[id]=123e4567-e89b-12d3-a456-426614174000&filter[tag]=x&filter[score]=9.5" +
+				"&fields=id,name&fields[people]=name&include=author,comments.author" +
+				"&page[limit]=10&page[offset]=20&sort=name",
+			bindQuery{
+				bindPaging: bindPaging{Limit: 10, Offset: 20},
+				Status:     "open",
+				MinAge:     &minAge,
+				Active:     &active,
+				Since:      time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				Timeout:    time.Minute,
+				IDs:        []UUID{{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}},
+				Tags:       []string{"x"},
+				Score:      9.5,
+				Sort:       []string{"name"},
+				Fields:     []string{"id", "name"},
+				People:     []string{"name"},
+				Include:    []string{"author", "comments.author"},
+			},
+		},
+		{
+			"multiple values into a string",
+			"filter[status]=open,closed&page[after]=abc",
+			bindQuery{
+				bindPaging: bindPaging{Limit: 25, After: "abc"},
+				Status:     "open,closed",
+				Tags:       []string{"a", "b"},
+				Sort:       []string{"-created"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bindQuery
+			if _, err := BindQuerystring(tt.qs, &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bind()\ngot:\n\t%+v\nwant:\n\t%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBind_errors(t *testing.T) {
+	q := bindQuery{bindPaging: bindPaging{Limit: 50}, Score: 5}
+	_, err := BindQuerystring("page[limit]=1000&filter[age][gte]=old&filter[active]=true,false&filter[score]=-1", &q)
+
+	var pes ParseErrors
+	if !errors.As(err, &pes) {
+		t.Fatalf("Bind() error = %v, want ParseErrors", err)
+	}
+
+	type result struct {
+		Code      ErrorCode
+		Parameter string
+	}
+
+	got := []result{}
+	for _, pe := range pes {
+		got = append(got, result{pe.Code, pe.Parameter})
+	}
+
+	want := []result{
+		{ErrCodeMaxSizeExceeded, "page[limit]"},
+		{ErrCodeInvalidValue, "filter[age][gte]"},
+		{ErrCodeInvalidValue, "filter[active]"},
+		{ErrCodeInvalidValue, "filter[score]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Bind() errors = %v, want %v", got, want)
+	}
+
+	// values outside of the limits do not replace the field values
+	if q.Limit != 50 || q.Score != 5 {
+		t.Errorf("Bind() limit = %v, score = %v, want 50, 5", q.Limit, q.Score)
+	}
+}
+
+func TestBind_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  any
+	}{
+		{"not a pointer", bindQuery{}},
+		{"nil pointer", (*bindQuery)(nil)},
+		{"not a struct", new(int)},
+		{"unsupported parameter", &struct {
+			A string `query:"other,a"`
+		}{}},
+		{"missing name", &struct {
+			A string `query:"filter"`
+		}{}},
+		{"unsupported operator", &struct {
+			A string `query:"filter,a,about"`
+		}{}},
+		{"unsupported option", &struct {
+			A string `query:"filter,a,size=1"`
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Bind(Options{}, tt.dst); err == nil {
+				t.Error("Bind() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package options
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CanonicalKey returns a normalized querystring that is identical for
+// semantically equal Options, suitable for use as a cache key
+//
+// Filters are ordered by field and operator, membership values are
+// ordered and de-duplicated, fields, fieldsets and include paths are
+// ordered and de-duplicated, and a + prefix is removed from sort
+// fields. The order of sort fields (and of between values) is retained
+// as it is significant.
+func (o Options) CanonicalKey() string {
+	c := o
+	c.Filter = nil
+	c.synced = nil
+	c.Filters = canonicalConditions(o.Conditions())
+	c.Fields = uniqueSorted(o.Fields)
+	c.Include = nil
+	c.Sort = canonicalSort(o.Sort)
+
+	if len(o.FieldSets) > 0 {
+		c.FieldSets = map[string][]string{}
+		for typ, fields := range o.FieldSets {
+			c.FieldSets[typ] = uniqueSorted(fields)
+		}
+	}
+
+	for _, path := range uniqueSorted(o.IncludePaths()) {
+		c.Include = addIncludePath(c.Include, strings.Split(path, "."))
+	}
+
+	// include pagination details even when no strategy is available
+	page := ""
+	if c.ps != nil && len(c.Page) > 0 {
+		page = c.ps.Current(c.Page)
+	} else if len(c.Page) > 0 {
+		keys := make([]string, 0, len(c.Page))
+		for k := range c.Page {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		params := make([]string, len(keys))
+		for i, k := range keys {
+			params[i] = fmt.Sprintf("page[%s]=%d", escapeKey(k), c.Page[k])
+		}
+
+		page = strings.Join(params, "&")
+	}
+
+	return c.querystring(page)
+}
+
+// Hash returns a hex encoded SHA-256 digest of the CanonicalKey,
+// suitable for use in an ETag
+func (o Options) Hash() string {
+	sum := sha256.Sum256([]byte(o.CanonicalKey()))
+	return hex.EncodeToString(sum[:])
+}
+
+func canonicalConditions(fcs []FilterCondition) []FilterCondition {
+	c := make([]FilterCondition, len(fcs))
+	for i, fc := range fcs {
+		c[i] = FilterCondition{fc.Field, fc.Operator, fc.Values}
+
+		// membership is unordered
+		if fc.Operator == OpIn || fc.Operator == OpNin {
+			c[i].Values = uniqueSorted(fc.Values)
+		}
+	}
+
+	sort.SliceStable(c, func(i, j int) bool {
+		if c[i].Field != c[j].Field {
+			return c[i].Field < c[j].Field
+		}
+
+		if c[i].Operator != c[j].Operator {
+			return c[i].Operator < c[j].Operator
+		}
+
+		return strings.Join(c[i].Values, ",") < strings.Join(c[j].Values, ",")
+	})
+
+	return c
+}
+
+func canonicalSort(fields []string) []string {
+	c := make([]string, 0, len(fields))
+	seen := map[string]bool{}
+
+	for _, field := range fields {
+		field = strings.TrimPrefix(field, "+")
+
+		// only the first occurrence of a field affects the order
+		name := strings.TrimPrefix(field, "-")
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		c = append(c, field)
+	}
+
+	return c
+}
+
+func uniqueSorted(values []string) []string {
+	c := make([]string, 0, len(values))
+	seen := map[string]bool{}
+
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			c = append(c, v)
+		}
+	}
+	sort.Strings(c)
+
+	return c
+}
@@ -0,0 +1,70 @@
+package options
+
+import "testing"
+
+func TestOptions_CanonicalKey(t *testing.T) {
+	tests := []struct {
+		name string
+		qsA  string
+		qsB  string
+		want string
+	}{
+		{
+			"filter order and membership values",
+			"filter[b]=2&filter[a]=y,x,x&page[limit]=10&page[offset]=0",
+			"page[offset]=0&filter[a]=x,y&page[limit]=10&filter[b]=2",
+			"filter[a]=x,y&filter[b]=2&page[limit]=10&page[offset]=0",
+		},
+		{
+			"fields, include and sort prefix",
+			"fields=b,a&include=comments.author,author,comments&sort=%2Bname,-age",
+			"include=author&include=comments.author&fields=a,b,a&sort=name,-age,name",
+			"fields=a,b&include=author,comments.author&sort=name,-age",
+		},
+		{
+			"operators and extra parameters",
+			"filter[age][lte]=65&filter[age][gte]=21&q=x&api=1",
+			"api=1&filter[age][gte]=21&q=x&filter[age][lte]=65",
+			"filter[age][gte]=21&filter[age][lte]=65&api=1&q=x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := FromQuerystring(tt.qsA)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := FromQuerystring(tt.qsB)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := a.CanonicalKey(); got != tt.want {
+				t.Errorf("Options.CanonicalKey() = %v, want %v", got, tt.want)
+			}
+
+			if a.Hash() != b.Hash() {
+				t.Errorf("Options.Hash() differs: %v != %v", a.CanonicalKey(), b.CanonicalKey())
+			}
+		})
+	}
+}
+
+func TestOptions_CanonicalKey_sortOrder(t *testing.T) {
+	a, _ := FromQuerystring("sort=name,age")
+	b, _ := FromQuerystring("sort=age,name")
+
+	if a.Hash() == b.Hash() {
+		t.Error("Options.Hash() should differ when sort order differs")
+	}
+}
+
+func TestOptions_CanonicalKey_page(t *testing.T) {
+	o := Options{Page: map[string]int{"offset": 10, "limit": 5}}
+
+	want := "page[limit]=5&page[offset]=10"
+	if got := o.CanonicalKey(); got != want {
+		t.Errorf("Options.CanonicalKey() = %v, want %v", got, want)
+	}
+}
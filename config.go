@@ -0,0 +1,319 @@
+package options
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LimitPolicy determines how a value that exceeds a limit is handled
+type LimitPolicy int
+
+// Limit policies supported by Config
+const (
+	// LimitError rejects Options that exceed a limit with a ParseError
+	LimitError LimitPolicy = iota
+
+	// LimitClamp reduces values that exceed a limit to the limit
+	LimitClamp
+)
+
+// Config supplies defaults that are applied to Options when values are
+// not provided and limits that restrict the values a client may provide,
+// unless noted otherwise a limit of 0 is not enforced
+type Config struct {
+	// DefaultPage contains page values (i.e. {"limit": 25}) applied for
+	// each page value that is not provided, a page position provided
+	// without a size (i.e. page[number]=2) is given the default size
+	DefaultPage map[string]int
+
+	// DefaultSort is applied when no sort fields are provided
+	DefaultSort []string
+
+	// DefaultFields is applied when no fields are provided
+	DefaultFields []string
+
+	// MaxPageSize is the largest page[limit] or page[size] permitted
+	MaxPageSize int
+
+	// MaxFilters is the largest number of filter conditions permitted,
+	// including the comparisons of the filter expression
+	MaxFilters int
+
+	// MaxFilterValues is the largest number of values permitted for a
+	// single filter condition or comparison of the filter expression
+	MaxFilterValues int
+
+	// MaxSort is the largest number of sort fields permitted
+	MaxSort int
+
+	// MaxFields is the largest number of fields permitted, both without a
+	// type and for each type of fields[type]
+	MaxFields int
+
+	// Policy determines whether values exceeding a limit are rejected or
+	// clamped to the limit
+	Policy LimitPolicy
+
+	// MaxQuerystringSize is the length (in bytes) of the largest querystring
+	// permitted, DefaultMaxQuerystringSize is used when 0 and the length is
+	// not limited when negative
+	MaxQuerystringSize int
+
+	// MaxExpressionDepth is the deepest nesting of groups and negations
+	// permitted in a filter expression, DefaultMaxExpressionDepth is used
+	// when 0 and the nesting is not limited when negative
+	MaxExpressionDepth int
+
+	// Cursor, when provided, applies the JSON:API cursor pagination profile
+	// so that a CursorStrategy is used even when no cursor is provided (i.e.
+	// for the first page)
+	Cursor *CursorPagination
+}
+
+// FromQuerystringWithConfig parses an Options object from the provided
+// querystring and applies the defaults and limits of the provided Config
+func FromQuerystringWithConfig(qs string, c Config) (Options, error) {
+	o, err := fromQuerystring(qs, &c)
+	if err != nil {
+		return o, err
+	}
+
+	if err := c.Apply(&o); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// Apply sets any default values that are not provided in the Options and
+// enforces the configured limits, returning ParseErrors when a limit is
+// exceeded and the Policy is LimitError
+func (c Config) Apply(o *Options) error {
+	c.applyDefaults(o)
+
+	var errs ParseErrors
+
+	// page size
+	for _, key := range []string{"limit", "size"} {
+		v, ok := o.Page[key]
+		if !ok || c.MaxPageSize <= 0 || v <= c.MaxPageSize {
+			continue
+		}
+
+		if c.Policy == LimitClamp {
+			o.Page[key] = c.MaxPageSize
+			continue
+		}
+
+		errs = append(errs, &ParseError{
+			Code:      ErrCodeMaxSizeExceeded,
+			Parameter: fmt.Sprintf("page[%s]", key),
+			Value:     fmt.Sprint(v),
+			Detail:    fmt.Sprintf("page size may not exceed %d", c.MaxPageSize),
+			Meta:      map[string]any{"page": map[string]any{"maxSize": c.MaxPageSize}},
+		})
+	}
+
+	// cursor pagination
+	if c.Cursor != nil {
+		if err := c.Cursor.Apply(o); err != nil {
+			pes, _ := err.(ParseErrors)
+			errs = append(errs, pes...)
+		}
+	}
+
+	// filters, including the comparisons of the filter expression, which
+	// can not be clamped without changing its meaning, so only bracketed
+	// filters are removed to make room for it
+	fcs := o.Conditions()
+	n := len(exprConditions(o.Expression))
+	clamped := false
+
+	if c.MaxFilters > 0 && len(fcs)+n > c.MaxFilters {
+		if c.Policy == LimitClamp && n <= c.MaxFilters {
+			fcs = fcs[:c.MaxFilters-n]
+			clamped = true
+		} else {
+			errs = append(errs, c.limitError("filter", len(fcs)+n, c.MaxFilters, "filters"))
+		}
+	}
+
+	for i, fc := range fcs {
+		// between requires both values
+		if c.MaxFilterValues <= 0 || len(fc.Values) <= c.MaxFilterValues || fc.Operator == OpBetween {
+			continue
+		}
+
+		if c.Policy == LimitClamp {
+			fcs[i] = FilterCondition{fc.Field, fc.Operator, fc.Values[:c.MaxFilterValues]}
+			clamped = true
+			continue
+		}
+
+		errs = append(errs, c.limitError(fmt.Sprintf("filter[%s]", fc.Field), len(fc.Values), c.MaxFilterValues, "filter values"))
+	}
+
+	if clamped {
+		o.Filters = fcs
+		o.syncFilter()
+	}
+
+	if c.MaxFilterValues > 0 && o.Expression != nil {
+		o.Expression = Rewrite(o.Expression, func(e Expr) Expr {
+			cmp, ok := e.(Compare)
+			if !ok || len(cmp.Values) <= c.MaxFilterValues || cmp.Operator == OpBetween {
+				return e
+			}
+
+			if c.Policy == LimitClamp {
+				cmp.Values = cmp.Values[:c.MaxFilterValues]
+				return cmp
+			}
+
+			errs = append(errs, c.limitError("filter", len(cmp.Values), c.MaxFilterValues, "filter values"))
+			return e
+		})
+	}
+
+	// sorting
+	if c.MaxSort > 0 && len(o.Sort) > c.MaxSort {
+		if c.Policy == LimitClamp {
+			o.Sort = o.Sort[:c.MaxSort]
+		} else {
+			errs = append(errs, c.limitError("sort", len(o.Sort), c.MaxSort, "sort fields"))
+		}
+	}
+
+	// field projections
+	if c.MaxFields > 0 && len(o.Fields) > c.MaxFields {
+		if c.Policy == LimitClamp {
+			o.Fields = o.Fields[:c.MaxFields]
+		} else {
+			errs = append(errs, c.limitError("fields", len(o.Fields), c.MaxFields, "fields"))
+		}
+	}
+
+	types := make([]string, 0, len(o.FieldSets))
+	for typ := range o.FieldSets {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+
+	for _, typ := range types {
+		fields := o.FieldSets[typ]
+		if c.MaxFields <= 0 || len(fields) <= c.MaxFields {
+			continue
+		}
+
+		if c.Policy == LimitClamp {
+			o.FieldSets[typ] = fields[:c.MaxFields]
+			continue
+		}
+
+		errs = append(errs, c.limitError(fmt.Sprintf("fields[%s]", typ), len(fields), c.MaxFields, "fields"))
+	}
+
+	return errs.errs()
+}
+
+func (c Config) applyDefaults(o *Options) {
+	if o.Page == nil {
+		o.Page = map[string]int{}
+	}
+
+	if len(o.Cursor) == 0 && len(c.DefaultPage) > 0 {
+		c.applyDefaultPage(o)
+	}
+
+	if len(o.Sort) == 0 && len(c.DefaultSort) > 0 {
+		o.Sort = append([]string{}, c.DefaultSort...)
+	}
+
+	if len(o.Fields) == 0 && len(o.FieldSets) == 0 && len(c.DefaultFields) > 0 {
+		o.Fields = append([]string{}, c.DefaultFields...)
+	}
+}
+
+// pageSchemes contains the page keys of each pagination strategy, the
+// size followed by the position, in the order inferPaginationStrategy
+// gives them precedence
+var pageSchemes = [][]string{
+	{"size", "number"},
+	{"size", "page"},
+	{"limit", "offset"},
+}
+
+// applyDefaultPage fills in the page values that are not provided, when
+// the client provides page values of a strategy only the defaults of that
+// strategy are used so that the page size can not be omitted
+func (c Config) applyDefaultPage(o *Options) {
+	scheme := pageScheme(o.Page)
+
+	for k, v := range c.DefaultPage {
+		if _, ok := o.Page[k]; ok {
+			continue
+		}
+
+		if scheme == nil || contains(scheme, k, false) {
+			o.Page[k] = v
+		}
+	}
+
+	// the default size may be provided for another strategy
+	if scheme != nil {
+		if _, ok := o.Page[scheme[0]]; !ok {
+			for _, key := range []string{"limit", "size"} {
+				if v, ok := c.DefaultPage[key]; ok {
+					o.Page[scheme[0]] = v
+					break
+				}
+			}
+		}
+	}
+
+	inferPaginationStrategy(o)
+}
+
+// pageScheme returns the page keys of the strategy of the provided page
+// values, or nil when none of the values are recognized
+func pageScheme(page map[string]int) []string {
+	for _, scheme := range pageSchemes {
+		if _, ok := page[scheme[1]]; ok {
+			return scheme
+		}
+	}
+
+	for _, scheme := range pageSchemes {
+		if _, ok := page[scheme[0]]; ok {
+			return scheme
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) maxQuerystringSize() int {
+	if c.MaxQuerystringSize == 0 {
+		return DefaultMaxQuerystringSize
+	}
+
+	return c.MaxQuerystringSize
+}
+
+func (c *Config) maxExpressionDepth() int {
+	if c.MaxExpressionDepth == 0 {
+		return DefaultMaxExpressionDepth
+	}
+
+	return c.MaxExpressionDepth
+}
+
+func (c Config) limitError(param string, n int, max int, noun string) *ParseError {
+	return &ParseError{
+		Code:      ErrCodeLimitExceeded,
+		Parameter: param,
+		Value:     fmt.Sprint(n),
+		Detail:    fmt.Sprintf("no more than %d %s may be provided", max, noun),
+		Meta:      map[string]any{"max": max},
+	}
+}
@@ -0,0 +1,226 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfig_Apply(t *testing.T) {
+	limits := Config{
+		MaxPageSize:     100,
+		MaxFilters:      2,
+		MaxFilterValues: 3,
+		MaxSort:         2,
+		MaxFields:       2,
+	}
+
+	clamp := limits
+	clamp.Policy = LimitClamp
+
+	tests := []struct {
+		name      string
+		qs        string
+		c         Config
+		want      string
+		wantCodes []ErrorCode
+		wantParam []string
+	}{
+		{
+			"defaults applied",
+			"filter[status]=open",
+			Config{
+				DefaultPage:   map[string]int{"size": 25},
+				DefaultSort:   []string{"-created"},
+				DefaultFields: []string{"id", "name"},
+			},
+			"filter[status]=open&fields=id,name&page[size]=25&page[page]=0&sort=-created",
+			nil,
+			nil,
+		},
+		{
+			"defaults not applied when provided",
+			"fields[people]=name&page[limit]=10&sort=name",
+			Config{
+				DefaultPage:   map[string]int{"size": 25},
+				DefaultSort:   []string{"-created"},
+				DefaultFields: []string{"id", "name"},
+			},
+			"fields[people]=name&page[limit]=10&page[offset]=0&sort=name",
+			nil,
+			nil,
+		},
+		{
+			"default page size with an offset",
+			"page[offset]=50",
+			Config{DefaultPage: map[string]int{"limit": 25}, MaxPageSize: 100},
+			"page[limit]=25&page[offset]=50",
+			nil,
+			nil,
+		},
+		{
+			"default page size with a page number",
+			"page[number]=2",
+			Config{DefaultPage: map[string]int{"limit": 25}, MaxPageSize: 100},
+			"page[number]=2&page[size]=25",
+			nil,
+			nil,
+		},
+		{
+			"default page size with an unknown page value",
+			"page[foo]=1",
+			Config{DefaultPage: map[string]int{"limit": 25, "offset": 0}},
+			"page[limit]=25&page[offset]=0",
+			nil,
+			nil,
+		},
+		{
+			"default page values of another strategy are not applied",
+			"page[size]=10",
+			Config{DefaultPage: map[string]int{"limit": 25, "offset": 0}},
+			"page[size]=10&page[page]=0",
+			nil,
+			nil,
+		},
+		{
+			"within limits",
+			"filter[a]=1,2,3&filter[b]=2&fields=a,b&page[limit]=100&sort=a,-b",
+			limits,
+			"filter[a]=1,2,3&filter[b]=2&fields=a,b&page[limit]=100&page[offset]=0&sort=a,-b",
+			nil,
+			nil,
+		},
+		{
+			"limits exceeded",
+			"filter[a]=1,2,3,4&filter[b]=2&filter[c]=3&fields=a,b,c&fields[people]=a,b,c&page[limit]=1000000&sort=a,b,c",
+			limits,
+			"",
+			[]ErrorCode{
+				ErrCodeMaxSizeExceeded,
+				ErrCodeLimitExceeded,
+				ErrCodeLimitExceeded,
+				ErrCodeLimitExceeded,
+				ErrCodeLimitExceeded,
+				ErrCodeLimitExceeded,
+			},
+			[]string{"page[limit]", "filter", "filter[a]", "sort", "fields", "fields[people]"},
+		},
+		{
+			"limits clamped",
+			"filter[a]=1,2,3,4&filter[b][gte]=2&filter[c]=3&fields=a,b,c&fields[people]=a,b,c&page[size]=1000000&sort=a,b,c",
+			clamp,
+			"filter[a]=1,2,3&filter[b][gte]=2&fields=a,b&fields[people]=a,b&page[size]=100&page[page]=0&sort=a,b",
+			nil,
+			nil,
+		},
+		{
+			"cursor pagination for the first page",
+			"sort=-created",
+			Config{Cursor: &CursorPagination{DefaultSize: 10, MaxSize: 50}},
+			"page[size]=10&sort=-created",
+			nil,
+			nil,
+		},
+		{
+			"cursor pagination with a size",
+			"page[size]=20",
+			Config{Cursor: &CursorPagination{DefaultSize: 10, MaxSize: 50}},
+			"page[size]=20",
+			nil,
+			nil,
+		},
+		{
+			"cursor pagination max size exceeded",
+			"page[size]=100&page[after]=abc",
+			Config{Cursor: &CursorPagination{DefaultSize: 10, MaxSize: 50}},
+			"",
+			[]ErrorCode{ErrCodeMaxSizeExceeded},
+			[]string{"page[size]"},
+		},
+		{
+			"filter expression limits exceeded",
+			"filter[a]=1&filter=b eq 2 or c in (1,2,3,4)",
+			limits,
+			"",
+			[]ErrorCode{ErrCodeLimitExceeded, ErrCodeLimitExceeded},
+			[]string{"filter", "filter"},
+		},
+		{
+			"filter expression limits clamped",
+			"filter[a]=1&filter[b]=2&filter=c in (1,2,3,4)",
+			clamp,
+			"filter[a]=1&filter=c+in+%28%271%27%2C+%272%27%2C+%273%27%29",
+			nil,
+			nil,
+		},
+		{
+			"filter expression exceeding max filters is not clamped",
+			"filter=a eq 1 and b eq 2 and c eq 3",
+			clamp,
+			"",
+			[]ErrorCode{ErrCodeLimitExceeded},
+			[]string{"filter"},
+		},
+		{
+			"between is not clamped",
+			"filter[age][between]=18,65",
+			Config{MaxFilterValues: 1, Policy: LimitClamp},
+			"filter[age][between]=18,65",
+			nil,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystringWithConfig(tt.qs, tt.c)
+			if tt.wantCodes == nil {
+				if err != nil {
+					t.Fatalf("FromQuerystringWithConfig() error = %v", err)
+				}
+
+				if got := o.String(); got != tt.want {
+					t.Errorf("Options.String() = %v, want %v", got, tt.want)
+				}
+
+				return
+			}
+
+			pes, ok := err.(ParseErrors)
+			if !ok {
+				t.Fatalf("FromQuerystringWithConfig() error = %v, want ParseErrors", err)
+			}
+
+			var (
+				codes  []ErrorCode
+				params []string
+			)
+			for _, pe := range pes {
+				codes = append(codes, pe.Code)
+				params = append(params, pe.Parameter)
+			}
+
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("FromQuerystringWithConfig() codes = %v, want %v", codes, tt.wantCodes)
+			}
+
+			if !reflect.DeepEqual(params, tt.wantParam) {
+				t.Errorf("FromQuerystringWithConfig() parameters = %v, want %v", params, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestConfig_Apply_legacyFilter(t *testing.T) {
+	o, err := FromQuerystringWithConfig("filter[a]=1,2,3&filter[b][lt]=5&filter[c]=x", Config{
+		MaxFilters:      2,
+		MaxFilterValues: 2,
+		Policy:          LimitClamp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{"a": {"1", "2"}, "b": {"<5"}}
+	if !reflect.DeepEqual(o.Filter, want) {
+		t.Errorf("Config.Apply() filter = %v, want %v", o.Filter, want)
+	}
+}
@@ -0,0 +1,286 @@
+package options
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// CursorStrategy is a keyset pagination strategy for page[after],
+// page[before] and page[size] parameters
+//
+// After and Before contain the cursors provided in the querystring.
+// Because the cursors for adjacent pages depend on the records returned,
+// SetCursors should be called with the cursors of the first and last
+// records of the current page prior to generating Next or Prev links.
+// As records are located relative to a cursor rather than an offset,
+// CursorStrategy does not implement ILimitOffsetStrategy.
+type CursorStrategy struct {
+	After  string
+	Before string
+
+	first string
+	last  string
+}
+
+// EncodeCursor creates an opaque cursor from the sort-key values of
+// a record
+func EncodeCursor(values ...any) (string, error) {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor reads the sort-key values from a cursor created with
+// EncodeCursor into the provided destinations
+func DecodeCursor(cursor string, dst ...any) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(b, &values); err != nil {
+		return fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if len(values) != len(dst) {
+		return fmt.Errorf("invalid cursor: expected %d values, found %d", len(dst), len(values))
+	}
+
+	for i, v := range values {
+		if err := json.Unmarshal(v, dst[i]); err != nil {
+			return fmt.Errorf("invalid cursor: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SetCursors specifies the cursors of the first and last records on
+// the current page, used for generating Prev and Next links
+func (cs *CursorStrategy) SetCursors(first, last string) {
+	cs.first = first
+	cs.last = last
+}
+
+// Current returns a link to the current page
+func (cs CursorStrategy) Current(c map[string]int) string {
+	// read size
+	s, ok := c["size"]
+	if !ok {
+		// if size isn't provided, return whatever was passed in
+		return ""
+	}
+
+	qs := fmt.Sprintf("page[size]=%d", s)
+
+	if cs.After != "" {
+		qs = fmt.Sprintf("%s&page[after]=%s", qs, url.QueryEscape(cs.After))
+	}
+
+	if cs.Before != "" {
+		qs = fmt.Sprintf("%s&page[before]=%s", qs, url.QueryEscape(cs.Before))
+	}
+
+	return qs
+}
+
+// First returns a link to the first page
+func (cs CursorStrategy) First(c map[string]int) string {
+	// read size
+	s, ok := c["size"]
+	if !ok {
+		// if size isn't provided, return whatever was passed in
+		return ""
+	}
+
+	return fmt.Sprintf("page[size]=%d", s)
+}
+
+// Last is not supported for cursor pagination as the cursor of the
+// last page is not known, an empty value is returned
+func (cs CursorStrategy) Last(c map[string]int, total int) string {
+	return ""
+}
+
+// Next returns a link to the page following the last record of the
+// current page
+func (cs CursorStrategy) Next(c map[string]int) string {
+	// read size
+	s, ok := c["size"]
+	if !ok || cs.last == "" {
+		// if size or cursor aren't available, return whatever was passed in
+		return ""
+	}
+
+	return fmt.Sprintf("page[size]=%d&page[after]=%s", s, url.QueryEscape(cs.last))
+}
+
+// Prev returns a link to the page preceding the first record of the
+// current page
+func (cs CursorStrategy) Prev(c map[string]int) string {
+	// read size
+	s, ok := c["size"]
+	if !ok || cs.first == "" {
+		// if size or cursor aren't available, return whatever was passed in
+		return ""
+	}
+
+	return fmt.Sprintf("page[size]=%d&page[before]=%s", s, url.QueryEscape(cs.first))
+}
+
+// CursorPaginationProfile is the URI of the JSON:API cursor pagination
+// profile (see https://jsonapi.org/profiles/ethanresnick/cursor-pagination/)
+const CursorPaginationProfile = "https://jsonapi.org/profiles/ethanresnick/cursor-pagination/"
+
+// CursorPagination applies the page size rules of the JSON:API cursor
+// pagination profile
+type CursorPagination struct {
+	// DefaultSize is used when page[size] is not provided
+	DefaultSize int
+
+	// MaxSize is the largest page[size] permitted, when 0 any size
+	// is permitted
+	MaxSize int
+}
+
+// CursorResult describes the records retrieved for the current page
+type CursorResult struct {
+	// FirstCursor and LastCursor are the cursors of the first and last
+	// records of the page
+	FirstCursor string
+	LastCursor  string
+
+	// HasMore indicates that additional records exist beyond the page
+	// in the direction of pagination (or beyond page[size] records when
+	// both page[after] and page[before] are provided)
+	HasMore bool
+
+	// EstimatedTotal is the estimated number of records in the
+	// collection, when known
+	EstimatedTotal *int
+}
+
+// CursorLinks contains the prev and next links of a collection, each
+// is null when there is no page in that direction
+type CursorLinks struct {
+	Prev *string `json:"prev"`
+	Next *string `json:"next"`
+}
+
+// CursorMeta contains the page member of a collection's meta object
+type CursorMeta struct {
+	Page CursorPageMeta `json:"page"`
+}
+
+// CursorPageMeta contains the meta.page details defined by the cursor
+// pagination profile
+type CursorPageMeta struct {
+	EstimatedTotal *EstimatedTotal `json:"estimatedTotal,omitempty"`
+	RangeTruncated bool            `json:"rangeTruncated,omitempty"`
+}
+
+// EstimatedTotal is the estimated number of records in a collection
+type EstimatedTotal struct {
+	BestGuess int `json:"bestGuess"`
+}
+
+// Apply validates page[size], applying DefaultSize when it is not
+// provided, and configures the Options to use a CursorStrategy
+func (cp CursorPagination) Apply(o *Options) error {
+	if o.Page == nil {
+		o.Page = map[string]int{}
+	}
+
+	size, ok := o.Page["size"]
+	switch {
+	case !ok && cp.DefaultSize > 0:
+		o.Page["size"] = cp.DefaultSize
+	case ok && size < 1:
+		return ParseErrors{{
+			Code:      ErrCodeInvalidValue,
+			Parameter: "page[size]",
+			Value:     fmt.Sprint(size),
+			Detail:    "value must be a positive integer",
+		}}
+	case ok && cp.MaxSize > 0 && size > cp.MaxSize:
+		return ParseErrors{{
+			Code:      ErrCodeMaxSizeExceeded,
+			Parameter: "page[size]",
+			Value:     fmt.Sprint(size),
+			Detail:    fmt.Sprintf("page size may not exceed %d", cp.MaxSize),
+			Type:      CursorPaginationProfile + "max-size-exceeded",
+			Meta:      map[string]any{"page": map[string]any{"maxSize": cp.MaxSize}},
+		}}
+	}
+
+	if _, ok := o.ps.(*CursorStrategy); !ok {
+		o.SetPaginationStrategy(&CursorStrategy{
+			After:  o.Cursor["after"],
+			Before: o.Cursor["before"],
+		})
+	}
+
+	return nil
+}
+
+// Page returns the links and meta members for the current page as
+// defined by the cursor pagination profile
+func (cp CursorPagination) Page(o Options, r CursorResult) (CursorLinks, CursorMeta) {
+	var (
+		links CursorLinks
+		meta  CursorMeta
+	)
+
+	after := o.Cursor["after"]
+	before := o.Cursor["before"]
+
+	// use a copy of the strategy so the provided Options are unaffected
+	cs := CursorStrategy{After: after, Before: before}
+	cs.SetCursors(r.FirstCursor, r.LastCursor)
+	o.SetPaginationStrategy(&cs)
+
+	if r.EstimatedTotal != nil {
+		meta.Page.EstimatedTotal = &EstimatedTotal{BestGuess: *r.EstimatedTotal}
+	}
+
+	// an empty page has no cursors to paginate from
+	if r.FirstCursor == "" || r.LastCursor == "" {
+		return links, meta
+	}
+
+	var hasPrev, hasNext bool
+	switch {
+	case after != "" && before != "":
+		// range pagination
+		meta.Page.RangeTruncated = r.HasMore
+		hasPrev = true
+		hasNext = true
+	case before != "":
+		// paginating backwards, records exist after the page
+		hasPrev = r.HasMore
+		hasNext = true
+	default:
+		// paginating forwards, records exist before the page when
+		// a cursor was provided
+		hasPrev = after != ""
+		hasNext = r.HasMore
+	}
+
+	if hasPrev {
+		prev := o.Prev()
+		links.Prev = &prev
+	}
+
+	if hasNext {
+		next := o.Next()
+		links.Next = &next
+	}
+
+	return links, meta
+}
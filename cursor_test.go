@@ -0,0 +1,213 @@
+package options
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCursorStrategy(t *testing.T) {
+	o, err := FromQuerystring("filter[status]=open&page[size]=10&page[after]=abc&sort=-created")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs, ok := o.PaginationStrategy().(*CursorStrategy)
+	if !ok {
+		t.Fatalf("FromQuerystring() strategy = %T, want *CursorStrategy", o.PaginationStrategy())
+	}
+
+	if want := map[string]string{"after": "abc"}; !reflect.DeepEqual(o.Cursor, want) {
+		t.Errorf("FromQuerystring() cursor = %v, want %v", o.Cursor, want)
+	}
+
+	// cursor pages have no offset
+	if l, off, ok := o.LimitOffset(); ok {
+		t.Errorf("Options.LimitOffset() = %d, %d, %v, want false", l, off, ok)
+	}
+
+	if qs, ok := o.NextPage(100); ok {
+		t.Errorf("Options.NextPage() = %v, %v, want false", qs, ok)
+	}
+
+	tests := []struct {
+		name string
+		fn   func() string
+		want string
+	}{
+		{"current", o.String, "filter[status]=open&page[size]=10&page[after]=abc&sort=-created"},
+		{"first", o.First, "filter[status]=open&page[size]=10&sort=-created"},
+		{"next without cursors", o.Next, "filter[status]=open&sort=-created"},
+		{"prev without cursors", o.Prev, "filter[status]=open&sort=-created"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	cs.SetCursors("def", "ghi")
+
+	if got, want := o.Next(), "filter[status]=open&page[size]=10&page[after]=ghi&sort=-created"; got != want {
+		t.Errorf("Options.Next() = %v, want %v", got, want)
+	}
+
+	if got, want := o.Prev(), "filter[status]=open&page[size]=10&page[before]=def&sort=-created"; got != want {
+		t.Errorf("Options.Prev() = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeCursor(t *testing.T) {
+	c, err := EncodeCursor("2021-01-01T00:00:00Z", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		created string
+		id      int
+	)
+
+	if err := DecodeCursor(c, &created, &id); err != nil {
+		t.Fatal(err)
+	}
+
+	if created != "2021-01-01T00:00:00Z" || id != 42 {
+		t.Errorf("DecodeCursor() = %v, %v", created, id)
+	}
+
+	if err := DecodeCursor(c, &created); err == nil {
+		t.Error("DecodeCursor() expected error for mismatched value count")
+	}
+
+	if err := DecodeCursor("not a cursor!", &created); err == nil {
+		t.Error("DecodeCursor() expected error for invalid cursor")
+	}
+}
+
+func TestCursorPagination_Apply(t *testing.T) {
+	cp := CursorPagination{DefaultSize: 10, MaxSize: 50}
+
+	tests := []struct {
+		name     string
+		qs       string
+		wantSize int
+		wantErr  ErrorCode
+	}{
+		{"default size", "page[after]=abc", 10, ""},
+		{"provided size", "page[size]=25", 25, ""},
+		{"size exceeds maximum", "page[size]=51", 51, ErrCodeMaxSizeExceeded},
+		{"size is not positive", "page[size]=0", 0, ErrCodeInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a size that is not positive is rejected when parsed
+			o, err := FromQuerystring(tt.qs)
+			if err == nil {
+				err = cp.Apply(&o)
+			}
+
+			if tt.wantErr != "" {
+				pes, ok := err.(ParseErrors)
+				if !ok || pes[0].Code != tt.wantErr {
+					t.Errorf("CursorPagination.Apply() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if o.Page["size"] != tt.wantSize {
+				t.Errorf("CursorPagination.Apply() size = %d, want %d", o.Page["size"], tt.wantSize)
+			}
+
+			if _, ok := o.PaginationStrategy().(*CursorStrategy); !ok {
+				t.Errorf("CursorPagination.Apply() strategy = %T, want *CursorStrategy", o.PaginationStrategy())
+			}
+		})
+	}
+}
+
+func TestCursorPagination_Apply_errorDocument(t *testing.T) {
+	o, _ := FromQuerystring("page[size]=100")
+	err := CursorPagination{MaxSize: 50}.Apply(&o)
+
+	b, _ := json.Marshal(JSONAPIErrors(err))
+	want := `{"errors":[{"status":"400","code":"max_size_exceeded","title":"Invalid Query Parameter","detail":"page size may not exceed 50","source":{"parameter":"page[size]"},"links":{"type":"https://jsonapi.org/profiles/ethanresnick/cursor-pagination/max-size-exceeded"},"meta":{"page":{"maxSize":50}}}]}`
+	if string(b) != want {
+		t.Errorf("JSONAPIErrors()\ngot:\n\t%s\nwant:\n\t%s", b, want)
+	}
+}
+
+func TestCursorPagination_Page(t *testing.T) {
+	cp := CursorPagination{DefaultSize: 2}
+	total := 100
+
+	tests := []struct {
+		name string
+		qs   string
+		r    CursorResult
+		want string
+	}{
+		{
+			"first page with more records",
+			"",
+			CursorResult{"c1", "c2", true, &total},
+			`{"links":{"prev":null,"next":"page[size]=2&page[after]=c2"},"meta":{"page":{"estimatedTotal":{"bestGuess":100}}}}`,
+		},
+		{
+			"last page paginating forwards",
+			"page[after]=c2",
+			CursorResult{"c3", "c4", false, nil},
+			`{"links":{"prev":"page[size]=2&page[before]=c3","next":null},"meta":{"page":{}}}`,
+		},
+		{
+			"paginating backwards",
+			"page[before]=c3",
+			CursorResult{"c1", "c2", false, nil},
+			`{"links":{"prev":null,"next":"page[size]=2&page[after]=c2"},"meta":{"page":{}}}`,
+		},
+		{
+			"truncated range",
+			"page[after]=c1&page[before]=c9",
+			CursorResult{"c2", "c3", true, nil},
+			`{"links":{"prev":"page[size]=2&page[before]=c2","next":"page[size]=2&page[after]=c3"},"meta":{"page":{"rangeTruncated":true}}}`,
+		},
+		{
+			"empty page",
+			"page[after]=c9",
+			CursorResult{},
+			`{"links":{"prev":null,"next":null},"meta":{"page":{}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cp.Apply(&o); err != nil {
+				t.Fatal(err)
+			}
+
+			links, meta := cp.Page(o, tt.r)
+
+			b := strings.Builder{}
+			enc := json.NewEncoder(&b)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(map[string]any{"links": links, "meta": meta}); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := strings.TrimSpace(b.String()); got != tt.want {
+				t.Errorf("CursorPagination.Page()\ngot:\n\t%s\nwant:\n\t%s", got, tt.want)
+			}
+		})
+	}
+}
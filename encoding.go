@@ -0,0 +1,158 @@
+package options
+
+import (
+	"net/url"
+	"strings"
+)
+
+// splitValues separates a decoded, comma separated parameter value into
+// its individual values
+//
+// A comma that is part of a value may be expressed by escaping it with
+// a backslash (i.e. a\,b) or by quoting the value (i.e. "a,b"). A single
+// space on either side of a separating comma is ignored.
+func splitValues(value string) []string {
+	// values without escapes or quotes are not copied
+	if !strings.ContainsAny(value, `\"`) {
+		return splitPlainValues(value)
+	}
+
+	return splitEscapedValues(value)
+}
+
+// splitEscapedValues separates a comma separated parameter value that
+// may contain escapes or quotes
+func splitEscapedValues(value string) []string {
+	var (
+		values []string
+		b      strings.Builder
+		quoted bool
+		start  = true
+	)
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case c == '\\' && i+1 < len(value) && isEscapable(value[i+1]):
+			// escaped character
+			i++
+			b.WriteByte(value[i])
+		case c == '"' && (start || quoted):
+			// opening or closing quote
+			quoted = !quoted
+		case c == ',' && !quoted:
+			values = append(values, strings.TrimSuffix(b.String(), " "))
+			b.Reset()
+			start = true
+
+			// ignore a single space following the comma
+			if i+1 < len(value) && value[i+1] == ' ' {
+				i++
+			}
+
+			continue
+		default:
+			b.WriteByte(c)
+		}
+
+		start = false
+	}
+
+	if len(values) == 0 {
+		return []string{b.String()}
+	}
+
+	return append(values, b.String())
+}
+
+// splitPlainValues separates a comma separated parameter value that
+// contains no escapes or quotes, in the same manner as splitValues
+func splitPlainValues(value string) []string {
+	values := make([]string, 0, strings.Count(value, ",")+1)
+
+	for {
+		v, rest, found := strings.Cut(value, ",")
+		if !found {
+			return append(values, v)
+		}
+
+		values = append(values, strings.TrimSuffix(v, " "))
+
+		// ignore a single space following the comma
+		value = strings.TrimPrefix(rest, " ")
+	}
+}
+
+// splitNames separates a comma separated list of field names or
+// relationship paths, returning a ParseError for the parameter when a
+// name contains a character that splitValues interprets or surrounding
+// whitespace, as it could not be written to a querystring unchanged
+func splitNames(param string, value string) ([]string, *ParseError) {
+	names := splitValues(value)
+
+	for _, name := range names {
+		if strings.ContainsAny(name, `\,"`) || strings.TrimSpace(name) != name {
+			return nil, &ParseError{
+				Code:      ErrCodeInvalidValue,
+				Parameter: param,
+				Value:     name,
+				Detail:    "name may not contain commas, quotes, backslashes or surrounding whitespace",
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// joinValues escapes each value so that it may be read by splitValues,
+// encodes it for use in a querystring and joins the values with commas
+func joinValues(values []string) string {
+	b := strings.Builder{}
+
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(url.QueryEscape(escapeValue(v)))
+	}
+
+	return b.String()
+}
+
+// joinNames encodes field names and relationship paths, which do not
+// contain the characters splitValues interprets (see splitNames), for use
+// in a querystring and joins them with commas
+func joinNames(names []string) string {
+	return strings.ReplaceAll(url.QueryEscape(strings.Join(names, ",")), "%2C", ",")
+}
+
+// escapeValue adds a backslash before any character that splitValues
+// would otherwise interpret
+func escapeValue(value string) string {
+	if !strings.ContainsAny(value, `\,"`) {
+		return value
+	}
+
+	b := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		if isEscapable(value[i]) {
+			b.WriteByte('\\')
+		}
+
+		b.WriteByte(value[i])
+	}
+
+	return b.String()
+}
+
+func isEscapable(c byte) bool {
+	return c == '\\' || c == ',' || c == '"'
+}
+
+// escapeKey encodes the name within a bracketed parameter (i.e. the
+// field in filter[field]) for use in a querystring
+func escapeKey(key string) string {
+	return url.QueryEscape(key)
+}
@@ -0,0 +1,197 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_splitValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{"empty value", "", []string{""}},
+		{"single value", "value", []string{"value"}},
+		{"multiple values", "value1,value2", []string{"value1", "value2"}},
+		{"spaces around commas", "value1 , value2", []string{"value1", "value2"}},
+		{"escaped comma", `a\,b,c`, []string{"a,b", "c"}},
+		{"quoted value", `"a,b",c`, []string{"a,b", "c"}},
+		{"quote within a value", `say "hi",c`, []string{`say "hi"`, "c"}},
+		{"escaped quote", `\"a,b\"`, []string{`"a`, `b"`}},
+		{"backslash that is not an escape", `a\b`, []string{`a\b`}},
+		{"empty values", ",", []string{"", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitValues(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitValues() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_joinValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{"single value", []string{"value"}, "value"},
+		{"multiple values", []string{"value1", "value2"}, "value1,value2"},
+		{"reserved characters", []string{"Smith & Sons", "a=b"}, "Smith+%26+Sons,a%3Db"},
+		{"literal comma", []string{"a,b", "c"}, "a%5C%2Cb,c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinValues(tt.values); got != tt.want {
+				t.Errorf("joinValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromQuerystring_encoding(t *testing.T) {
+	tests := []struct {
+		name       string
+		qs         string
+		wantFilter map[string][]string
+		wantExtra  string
+	}{
+		{
+			"encoded ampersand in a filter value",
+			"filter[customer]=Smith%20%26%20Sons&q=a%3Db",
+			map[string][]string{"customer": {"Smith & Sons"}},
+			"a=b",
+		},
+		{
+			"escaped comma in a filter value",
+			"filter[name]=Doe%5C%2C%20Jane,Roe",
+			map[string][]string{"name": {"Doe, Jane", "Roe"}},
+			"",
+		},
+		{
+			"quoted filter value",
+			"filter[name]=%22Doe,%20Jane%22,Roe",
+			map[string][]string{"name": {"Doe, Jane", "Roe"}},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got.Filter, tt.wantFilter) {
+				t.Errorf("FromQuerystring() filter = %q, want %q", got.Filter, tt.wantFilter)
+			}
+
+			if q := got.Extra.Get("q"); q != tt.wantExtra {
+				t.Errorf("FromQuerystring() extra = %q, want %q", q, tt.wantExtra)
+			}
+		})
+	}
+}
+
+func TestFromQuerystring_names(t *testing.T) {
+	tests := []struct {
+		name      string
+		qs        string
+		wantParam string
+	}{
+		{"names", "fields=id,name&fields[people]=name&include=author,comments.author&sort=-name,+age", ""},
+		{"escaped comma", `fields=a\,b`, "fields"},
+		{"quoted comma", "include=%22,%22", "include"},
+		{"whitespace", "include=%20%20,x", "include"},
+		{"surrounding whitespace", "fields[people]=%20name", "fields[people]"},
+		{"backslash", `sort=-a\b`, "sort"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if tt.wantParam != "" {
+				pes, ok := err.(ParseErrors)
+				if !ok || len(pes) != 1 || pes[0].Code != ErrCodeInvalidValue || pes[0].Parameter != tt.wantParam {
+					t.Errorf("FromQuerystring() error = %v, want invalid value of %s", err, tt.wantParam)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// names are written so that they are read unchanged
+			got, err := FromQuerystring(o.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got.Fields, o.Fields) || !reflect.DeepEqual(got.FieldSets, o.FieldSets) ||
+				!reflect.DeepEqual(got.Include, o.Include) || !reflect.DeepEqual(got.Sort, o.Sort) {
+				t.Errorf("FromQuerystring(%q) = %+v, want %+v", o.String(), got, o)
+			}
+		})
+	}
+}
+
+func TestFromQuerystring_invalidEncoding(t *testing.T) {
+	_, err := FromQuerystring("filter[name]=%zz")
+
+	pes, ok := err.(ParseErrors)
+	if !ok || len(pes) != 1 || pes[0].Code != ErrCodeInvalidEncoding || pes[0].Parameter != "filter[name]" {
+		t.Errorf("FromQuerystring() error = %v, want invalid encoding of filter[name]", err)
+	}
+}
+
+func TestOptions_String_roundTrip(t *testing.T) {
+	o := Options{
+		Extra: map[string][]string{"q": {"a&b=c"}},
+		Filters: []FilterCondition{
+			{"customer", OpEq, []string{"Smith & Sons"}},
+			{"name", OpIn, []string{"Doe, Jane", `say "hi"`, `back\slash`}},
+			{"age", OpGte, []string{"21"}},
+			{"rating", OpEq, []string{"<5"}},
+			{"code", OpEq, []string{"!=x"}},
+			{"size", OpIn, []string{">=1", "2"}},
+		},
+		Page: map[string]int{"limit": 10, "offset": 20},
+		Sort: []string{"-name", "+age"},
+	}
+	o.SetPaginationStrategy(&OffsetStrategy{})
+
+	got, err := FromQuerystring(o.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got.Filters, o.Filters) {
+		t.Errorf("FromQuerystring() filters = %q, want %q", got.Filters, o.Filters)
+	}
+
+	if !reflect.DeepEqual(got.Sort, o.Sort) {
+		t.Errorf("FromQuerystring() sort = %q, want %q", got.Sort, o.Sort)
+	}
+
+	if !reflect.DeepEqual(got.Page, o.Page) {
+		t.Errorf("FromQuerystring() page = %v, want %v", got.Page, o.Page)
+	}
+
+	if !reflect.DeepEqual(got.Extra, o.Extra) {
+		t.Errorf("FromQuerystring() extra = %q, want %q", got.Extra, o.Extra)
+	}
+}
+
+func Test_splitPlainValues(t *testing.T) {
+	// values without escapes or quotes split identically to the general case
+	for _, value := range []string{"", "value", "a,b", "a , b", "a  ,  b ", " a,b", ",", ",,", "a,", ", a"} {
+		t.Run(value, func(t *testing.T) {
+			if got, want := splitPlainValues(value), splitEscapedValues(value); !reflect.DeepEqual(got, want) {
+				t.Errorf("splitPlainValues() = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrorCode identifies the reason a querystring parameter was rejected
+type ErrorCode string
+
+// Error codes assigned to each ParseError
+const (
+	ErrCodeInvalidEncoding      ErrorCode = "invalid_encoding"
+	ErrCodeInvalidHierarchy     ErrorCode = "invalid_hierarchy"
+	ErrCodeInvalidValue         ErrorCode = "invalid_value"
+	ErrCodeUnsupportedOperator  ErrorCode = "unsupported_operator"
+	ErrCodeOperatorNotPermitted ErrorCode = "operator_not_permitted"
+	ErrCodeNotFilterable        ErrorCode = "not_filterable"
+	ErrCodeNotSortable          ErrorCode = "not_sortable"
+	ErrCodeNotSelectable        ErrorCode = "not_selectable"
+	ErrCodeMaxSizeExceeded      ErrorCode = "max_size_exceeded"
+	ErrCodeNotIncludable        ErrorCode = "not_includable"
+	ErrCodeIncludeTooDeep       ErrorCode = "include_too_deep"
+	ErrCodeLimitExceeded        ErrorCode = "limit_exceeded"
+	ErrCodeInvalidExpression    ErrorCode = "invalid_expression"
+	ErrCodeExpressionTooDeep    ErrorCode = "expression_too_deep"
+)
+
+// ParseError describes a querystring parameter that could not be parsed
+// or is not permitted
+//
+// Header is used in place of Parameter when the value was provided via a
+// request header (i.e. Range). Type and Meta are optional, and are used
+// when an error type URI and additional details are required (i.e. by a
+// JSON:API profile)
+type ParseError struct {
+	Code      ErrorCode
+	Parameter string
+	Header    string
+	Value     string
+	Detail    string
+	Err       error
+	Type      string
+	Meta      map[string]any
+}
+
+// Error returns a description of the parse failure
+func (pe *ParseError) Error() string {
+	if pe.Header != "" {
+		return fmt.Sprintf("unable to parse %s header: %s", pe.Header, pe.Detail)
+	}
+
+	if pe.Parameter == "" {
+		return fmt.Sprintf("unable to parse: %s", pe.Detail)
+	}
+
+	return fmt.Sprintf("unable to parse %s: %s", pe.Parameter, pe.Detail)
+}
+
+// Unwrap returns the underlying error, if any
+func (pe *ParseError) Unwrap() error {
+	return pe.Err
+}
+
+// ParseErrors is a collection of each ParseError encountered while
+// parsing or validating a querystring
+type ParseErrors []*ParseError
+
+// Error returns a description of each parse failure
+func (pes ParseErrors) Error() string {
+	msgs := make([]string, len(pes))
+	for i, pe := range pes {
+		msgs[i] = pe.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns each ParseError for use with errors.Is and errors.As
+func (pes ParseErrors) Unwrap() []error {
+	errs := make([]error, len(pes))
+	for i, pe := range pes {
+		errs[i] = pe
+	}
+
+	return errs
+}
+
+// ErrorObject is a JSON:API error object
+// (see https://jsonapi.org/format/#error-objects)
+type ErrorObject struct {
+	Status string         `json:"status,omitempty"`
+	Code   string         `json:"code,omitempty"`
+	Title  string         `json:"title,omitempty"`
+	Detail string         `json:"detail,omitempty"`
+	Source *ErrorSource   `json:"source,omitempty"`
+	Links  *ErrorLinks    `json:"links,omitempty"`
+	Meta   map[string]any `json:"meta,omitempty"`
+}
+
+// ErrorLinks contains links that describe an error, including a type
+// link identifying the kind of error
+type ErrorLinks struct {
+	Type string `json:"type,omitempty"`
+}
+
+// ErrorSource identifies the querystring parameter or request header that
+// caused an error
+type ErrorSource struct {
+	Parameter string `json:"parameter,omitempty"`
+	Header    string `json:"header,omitempty"`
+}
+
+// ErrorDocument is a JSON:API top-level document containing errors
+type ErrorDocument struct {
+	Errors []ErrorObject `json:"errors"`
+}
+
+// JSONAPIErrors renders the provided error as a JSON:API document with
+// an error object for each ParseError, errors of any other type are
+// rendered as a single error object without a source
+func JSONAPIErrors(err error) ErrorDocument {
+	doc := ErrorDocument{Errors: []ErrorObject{}}
+	if err == nil {
+		return doc
+	}
+
+	var pes ParseErrors
+	if errors.As(err, &pes) {
+		for _, pe := range pes {
+			doc.Errors = append(doc.Errors, pe.errorObject())
+		}
+
+		return doc
+	}
+
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		doc.Errors = append(doc.Errors, pe.errorObject())
+		return doc
+	}
+
+	doc.Errors = append(doc.Errors, ErrorObject{
+		Status: strconv.Itoa(http.StatusBadRequest),
+		Title:  http.StatusText(http.StatusBadRequest),
+		Detail: err.Error(),
+	})
+
+	return doc
+}
+
+func (pe *ParseError) errorObject() ErrorObject {
+	eo := ErrorObject{
+		Status: strconv.Itoa(http.StatusBadRequest),
+		Code:   string(pe.Code),
+		Title:  "Invalid Query Parameter",
+		Detail: pe.Detail,
+		Meta:   pe.Meta,
+	}
+
+	// errors of the querystring as a whole (i.e. its size) have no source
+	if pe.Parameter != "" || pe.Header != "" {
+		eo.Source = &ErrorSource{Parameter: pe.Parameter, Header: pe.Header}
+	}
+
+	if pe.Header != "" {
+		eo.Title = "Invalid Header"
+	}
+
+	if pe.Type != "" {
+		eo.Links = &ErrorLinks{Type: pe.Type}
+	}
+
+	return eo
+}
+
+// errs returns the collected errors as an error, or nil when empty
+func (pes ParseErrors) errs() error {
+	if len(pes) == 0 {
+		return nil
+	}
+
+	return pes
+}
@@ -0,0 +1,116 @@
+package options
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJSONAPIErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorDocument
+	}{
+		{"no error", nil, ErrorDocument{Errors: []ErrorObject{}}},
+		{
+			"parse error",
+			&ParseError{Code: ErrCodeInvalidValue, Parameter: "page[limit]", Value: "abc", Detail: "value must be an integer"},
+			ErrorDocument{Errors: []ErrorObject{
+				{Status: "400", Code: "invalid_value", Title: "Invalid Query Parameter", Detail: "value must be an integer", Source: &ErrorSource{Parameter: "page[limit]"}},
+			}},
+		},
+		{
+			"multiple parse errors",
+			ParseErrors{
+				{Code: ErrCodeInvalidValue, Parameter: "page[limit]", Value: "abc", Detail: "value must be an integer"},
+				{Code: ErrCodeNotSortable, Parameter: "sort", Value: "secret", Detail: `field "secret" is not sortable`},
+			},
+			ErrorDocument{Errors: []ErrorObject{
+				{Status: "400", Code: "invalid_value", Title: "Invalid Query Parameter", Detail: "value must be an integer", Source: &ErrorSource{Parameter: "page[limit]"}},
+				{Status: "400", Code: "not_sortable", Title: "Invalid Query Parameter", Detail: `field "secret" is not sortable`, Source: &ErrorSource{Parameter: "sort"}},
+			}},
+		},
+		{
+			"other error",
+			errors.New("something went wrong"),
+			ErrorDocument{Errors: []ErrorObject{
+				{Status: "400", Title: "Bad Request", Detail: "something went wrong"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JSONAPIErrors(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JSONAPIErrors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromQuerystring_parseErrors(t *testing.T) {
+	_, err := FromQuerystring("page[limit]=abc&filter[age][around]=21&page[offset]=10")
+
+	var pes ParseErrors
+	if !errors.As(err, &pes) {
+		t.Fatalf("FromQuerystring() error = %v, want ParseErrors", err)
+	}
+
+	if len(pes) != 2 {
+		t.Fatalf("FromQuerystring() returned %d errors, want 2", len(pes))
+	}
+
+	b, err := json.Marshal(JSONAPIErrors(pes))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"errors":[{"status":"400","code":"invalid_value","title":"Invalid Query Parameter","detail":"value must be an integer","source":{"parameter":"page[limit]"}},{"status":"400","code":"unsupported_operator","title":"Invalid Query Parameter","detail":"unsupported filter operator \"around\"","source":{"parameter":"filter[age][around]"}}]}`
+	if string(b) != want {
+		t.Errorf("JSONAPIErrors()\ngot:\n\t%s\nwant:\n\t%s", b, want)
+	}
+}
+
+func TestFromQuerystring_pageValues(t *testing.T) {
+	tests := []struct {
+		qs      string
+		wantErr bool
+	}{
+		{"page[limit]=1&page[offset]=0", false},
+		{"page[number]=0&page[size]=1", false},
+		{"page[limit]=0", true},
+		{"page[limit]=-1", true},
+		{"page[size]=0", true},
+		{"page[offset]=-4", true},
+		{"page[size]=10&page[page]=-1", true},
+		{"page[number]=-1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.qs, func(t *testing.T) {
+			_, err := FromQuerystring(tt.qs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromQuerystring() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var pes ParseErrors
+			if tt.wantErr && (!errors.As(err, &pes) || pes[0].Code != ErrCodeInvalidValue) {
+				t.Errorf("FromQuerystring() error = %v, want %s", err, ErrCodeInvalidValue)
+			}
+		})
+	}
+}
+
+func TestJSONAPIErrors_withoutSource(t *testing.T) {
+	_, err := FromQuerystringWithConfig("sort=name&page[limit]=10", Config{MaxQuerystringSize: 8})
+
+	b, err := json.Marshal(JSONAPIErrors(err))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"errors":[{"status":"400","code":"limit_exceeded","title":"Invalid Query Parameter","detail":"querystring may not exceed 8 bytes","meta":{"max":8}}]}`
+	if string(b) != want {
+		t.Errorf("JSONAPIErrors()\ngot:\n\t%s\nwant:\n\t%s", b, want)
+	}
+}
@@ -23,16 +23,8 @@ func handler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// handle pagination
-	limit := 100
-	offset := 0
-
-	if l, ok := opt.Page["limit"]; ok {
-		limit = l
-	}
-
-	if o, ok := opt.Page["offset"]; ok {
-		offset = o
-	}
+	limit := opt.PageInt("limit", 100)
+	offset := opt.PageInt("offset", 0)
 
 	fmt.Println("pagination provided with a limit of ", limit, ", and an offset of ", offset)
 
@@ -0,0 +1,455 @@
+package options
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultMaxExpressionDepth is the deepest nesting of groups and negations
+// permitted in a filter expression when Config.MaxExpressionDepth is 0,
+// bounding the cost of parsing and evaluating a single expression
+const DefaultMaxExpressionDepth = 16
+
+// Expr is a node of a boolean filter expression provided via the filter
+// parameter (i.e. filter=status eq 'open' or priority gt 3)
+type Expr interface {
+	// Accept calls the method of the Visitor for the type of the node
+	Accept(v Visitor) error
+
+	// String returns the expression in the form accepted by the filter
+	// parameter
+	String() string
+}
+
+// Visitor is implemented by backends that compile filter expressions,
+// each method is responsible for visiting the children of the node (i.e.
+// by calling Left.Accept)
+type Visitor interface {
+	VisitAnd(And) error
+	VisitOr(Or) error
+	VisitNot(Not) error
+	VisitCompare(Compare) error
+}
+
+// And is satisfied when both the Left and Right expressions are satisfied
+type And struct {
+	Left  Expr `json:"left"`
+	Right Expr `json:"right"`
+}
+
+// Or is satisfied when either the Left or Right expression is satisfied
+type Or struct {
+	Left  Expr `json:"left"`
+	Right Expr `json:"right"`
+}
+
+// Not is satisfied when the Expr is not satisfied
+type Not struct {
+	Expr Expr `json:"expr"`
+}
+
+// Compare is a single comparison of a field (i.e. priority gt 3)
+type Compare struct {
+	Field    string   `json:"field"`
+	Operator Operator `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+// Accept calls VisitAnd
+func (e And) Accept(v Visitor) error { return v.VisitAnd(e) }
+
+// Accept calls VisitOr
+func (e Or) Accept(v Visitor) error { return v.VisitOr(e) }
+
+// Accept calls VisitNot
+func (e Not) Accept(v Visitor) error { return v.VisitNot(e) }
+
+// Accept calls VisitCompare
+func (e Compare) Accept(v Visitor) error { return v.VisitCompare(e) }
+
+// String returns the expression, grouping an Or operand
+func (e And) String() string {
+	return fmt.Sprintf("%s and %s", groupExpr(e.Left, isOr), groupExpr(e.Right, isOr))
+}
+
+// String returns the expression
+func (e Or) String() string {
+	return fmt.Sprintf("%s or %s", e.Left, e.Right)
+}
+
+// String returns the expression, grouping an And or Or operand
+func (e Not) String() string {
+	return "not " + groupExpr(e.Expr, func(x Expr) bool { return isOr(x) || isAnd(x) })
+}
+
+// String returns the comparison with quoted values
+func (e Compare) String() string {
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = quoteExprValue(v)
+	}
+
+	switch e.Operator {
+	case OpIn, OpNin:
+		return fmt.Sprintf("%s %s (%s)", e.Field, e.Operator, strings.Join(values, ", "))
+	case OpBetween:
+		return fmt.Sprintf("%s %s %s", e.Field, e.Operator, strings.Join(values, " and "))
+	case OpNull:
+		// null is compared with a boolean rather than a quoted value
+		return fmt.Sprintf("%s %s %s", e.Field, e.Operator, strings.Join(e.Values, ""))
+	}
+
+	return fmt.Sprintf("%s %s %s", e.Field, e.Operator, strings.Join(values, ""))
+}
+
+// Condition returns the comparison as a FilterCondition
+func (e Compare) Condition() FilterCondition {
+	return FilterCondition{e.Field, e.Operator, e.Values}
+}
+
+func isAnd(e Expr) bool {
+	_, ok := e.(And)
+	return ok
+}
+
+func isOr(e Expr) bool {
+	_, ok := e.(Or)
+	return ok
+}
+
+func groupExpr(e Expr, group func(Expr) bool) string {
+	if group(e) {
+		return "(" + e.String() + ")"
+	}
+
+	return e.String()
+}
+
+func quoteExprValue(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
+// ParseExpression parses a boolean filter expression, in which
+// comparisons (field operator value) are combined with not, and and or
+// (in order of precedence) and grouped with parentheses:
+//
+//	(status eq 'open' or priority gt 3) and owner eq 'me'
+//	not (tags in ('a', 'b')) and age between 18 and 65
+//
+// Values are quoted with single quotes (a quote within a value is
+// escaped by repeating it), or may be provided without quotes when they
+// contain no spaces, commas or parentheses (i.e. 3 or true).
+func ParseExpression(expr string) (Expr, error) {
+	e, pe := parseExpression(expr, DefaultMaxExpressionDepth)
+	if pe != nil {
+		return nil, pe
+	}
+
+	return e, nil
+}
+
+// parseExpression parses the expression, permitting groups and negations
+// to be nested up to maxDepth levels (not limited when 0)
+func parseExpression(expr string, maxDepth int) (Expr, *ParseError) {
+	p := exprParser{lexer: exprLexer{input: expr}, maxDepth: maxDepth}
+	p.next()
+
+	e, pe := p.parseOr(0)
+	if pe != nil {
+		return nil, pe
+	}
+
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokLParen
+	tokRParen
+	tokComma
+	tokError
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return quoteExprValue(t.value)
+	}
+
+	return fmt.Sprintf("%q", t.value)
+}
+
+// keyword reports whether the token is the provided (case insensitive)
+// keyword
+func (t token) keyword(k string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.value, k)
+}
+
+type exprLexer struct {
+	input string
+	pos   int
+}
+
+func (l *exprLexer) next() token {
+	// skip whitespace
+	for l.pos < len(l.input) && isExprSpace(l.input[l.pos]) {
+		l.pos++
+	}
+
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{kind: tokEOF, pos: start}
+	}
+
+	switch c := l.input[l.pos]; c {
+	case '(':
+		l.pos++
+		return token{tokLParen, "(", start}
+	case ')':
+		l.pos++
+		return token{tokRParen, ")", start}
+	case ',':
+		l.pos++
+		return token{tokComma, ",", start}
+	case '\'':
+		b := strings.Builder{}
+		for l.pos++; l.pos < len(l.input); l.pos++ {
+			if l.input[l.pos] != '\'' {
+				b.WriteByte(l.input[l.pos])
+				continue
+			}
+
+			// a repeated quote is an escaped quote
+			if l.pos+1 < len(l.input) && l.input[l.pos+1] == '\'' {
+				b.WriteByte('\'')
+				l.pos++
+				continue
+			}
+
+			l.pos++
+			return token{tokString, b.String(), start}
+		}
+
+		return token{tokError, "unterminated string", start}
+	}
+
+	for l.pos < len(l.input) && !isExprSpace(l.input[l.pos]) && !strings.ContainsRune("(),'", rune(l.input[l.pos])) {
+		l.pos++
+	}
+
+	return token{tokWord, l.input[start:l.pos], start}
+}
+
+func isExprSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+type exprParser struct {
+	lexer    exprLexer
+	tok      token
+	maxDepth int
+}
+
+func (p *exprParser) next() {
+	p.tok = p.lexer.next()
+}
+
+func (p *exprParser) errorf(format string, args ...any) *ParseError {
+	return &ParseError{
+		Code:      ErrCodeInvalidExpression,
+		Parameter: "filter",
+		Value:     p.lexer.input,
+		Detail:    fmt.Sprintf("%s at position %d", fmt.Sprintf(format, args...), p.tok.pos+1),
+	}
+}
+
+func (p *exprParser) parseOr(depth int) (Expr, *ParseError) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.keyword("or") {
+		p.next()
+
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		left = Or{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseAnd(depth int) (Expr, *ParseError) {
+	left, err := p.parseNot(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.keyword("and") {
+		p.next()
+
+		right, err := p.parseNot(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		left = And{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *exprParser) parseNot(depth int) (Expr, *ParseError) {
+	if !p.tok.keyword("not") && p.tok.kind != tokLParen {
+		return p.parseCompare()
+	}
+
+	if p.maxDepth > 0 && depth >= p.maxDepth {
+		pe := p.errorf("expression may not be nested more than %d levels", p.maxDepth)
+		pe.Code = ErrCodeExpressionTooDeep
+		return nil, pe
+	}
+
+	if p.tok.keyword("not") {
+		p.next()
+
+		e, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		return Not{e}, nil
+	}
+
+	// grouped expression
+	p.next()
+
+	e, err := p.parseOr(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != tokRParen {
+		return nil, p.errorf("expected ) but found %s", p.tok)
+	}
+	p.next()
+
+	return e, nil
+}
+
+func (p *exprParser) parseCompare() (Expr, *ParseError) {
+	if p.tok.kind != tokWord || isExprKeyword(p.tok.value) {
+		return nil, p.errorf("expected a field but found %s", p.tok)
+	}
+
+	field := p.tok.value
+	p.next()
+
+	if p.tok.kind != tokWord {
+		return nil, p.errorf("expected an operator but found %s", p.tok)
+	}
+
+	op, err := ParseOperator(strings.ToLower(p.tok.value))
+	if err != nil {
+		return nil, p.errorf("unsupported operator %s", p.tok)
+	}
+	p.next()
+
+	var values []string
+
+	switch op {
+	case OpIn, OpNin:
+		if p.tok.kind != tokLParen {
+			return nil, p.errorf("expected ( but found %s", p.tok)
+		}
+
+		for {
+			p.next()
+
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, v)
+			if p.tok.kind != tokComma {
+				break
+			}
+		}
+
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("expected ) but found %s", p.tok)
+		}
+		p.next()
+	case OpBetween:
+		lo, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.tok.keyword("and") {
+			return nil, p.errorf("expected and but found %s", p.tok)
+		}
+		p.next()
+
+		hi, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		values = []string{lo, hi}
+	default:
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		values = []string{v}
+	}
+
+	// validate the number of values and null values
+	fc, pe := newFilterCondition(field, op, values)
+	if pe != nil {
+		return nil, p.errorf("%s for %s %s", pe.Detail, field, op)
+	}
+
+	return Compare(fc), nil
+}
+
+func (p *exprParser) parseValue() (string, *ParseError) {
+	switch {
+	case p.tok.kind == tokString, p.tok.kind == tokWord && !isExprKeyword(p.tok.value):
+		v := p.tok.value
+		p.next()
+
+		return v, nil
+	case p.tok.kind == tokError:
+		return "", p.errorf("%s", p.tok.value)
+	}
+
+	return "", p.errorf("expected a value but found %s", p.tok)
+}
+
+func isExprKeyword(word string) bool {
+	return strings.EqualFold(word, "and") || strings.EqualFold(word, "or") || strings.EqualFold(word, "not")
+}
@@ -0,0 +1,237 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	status := Compare{"status", OpEq, []string{"open"}}
+	priority := Compare{"priority", OpGt, []string{"3"}}
+	owner := Compare{"owner", OpEq, []string{"me"}}
+
+	tests := []struct {
+		name string
+		expr string
+		want Expr
+	}{
+		{"comparison", "status eq 'open'", status},
+		{"unquoted value", "priority gt 3", priority},
+		{"case insensitive keywords", "status EQ 'open' OR priority Gt 3", Or{status, priority}},
+		{"and before or", "status eq 'open' or priority gt 3 and owner eq me", Or{status, And{priority, owner}}},
+		{"grouping", "(status eq 'open' or priority gt 3) and owner eq 'me'", And{Or{status, priority}, owner}},
+		{"left associative", "status eq open and priority gt 3 and owner eq me", And{And{status, priority}, owner}},
+		{"not before and", "not status eq 'open' and owner eq 'me'", And{Not{status}, owner}},
+		{"not group", "not (status eq 'open' or owner eq 'me')", Not{Or{status, owner}}},
+		{"membership", "tag in ('a', 'b,c', d)", Compare{"tag", OpIn, []string{"a", "b,c", "d"}}},
+		{"between", "age between 18 and 65 and owner eq me", And{Compare{"age", OpBetween, []string{"18", "65"}}, owner}},
+		{"null", "deleted null true", Compare{"deleted", OpNull, []string{"true"}}},
+		{"escaped quote", "name eq 'O''Brien'", Compare{"name", OpEq, []string{"O'Brien"}}},
+		{"keyword within a string", "name eq 'this and that'", Compare{"name", OpEq, []string{"this and that"}}},
+		{"nested field", "author.name like 'Jo*'", Compare{"author.name", OpLike, []string{"Jo*"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExpression(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExpression() = %#v, want %#v", got, tt.want)
+			}
+
+			// the string form parses to the same expression
+			again, err := ParseExpression(got.String())
+			if err != nil {
+				t.Fatalf("ParseExpression(%q) error = %v", got.String(), err)
+			}
+
+			if !reflect.DeepEqual(again, got) {
+				t.Errorf("ParseExpression(%q) = %#v, want %#v", got.String(), again, got)
+			}
+		})
+	}
+}
+
+func TestParseExpression_errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expr       string
+		wantCode   ErrorCode
+		wantDetail string
+	}{
+		{"missing value", "status eq", ErrCodeInvalidExpression, "expected a value but found end of expression at position 10"},
+		{"unsupported operator", "status is 'open'", ErrCodeInvalidExpression, `unsupported operator "is" at position 8`},
+		{"missing field", "and status eq 'open'", ErrCodeInvalidExpression, `expected a field but found "and" at position 1`},
+		{"unterminated string", "status eq 'open", ErrCodeInvalidExpression, "unterminated string at position 11"},
+		{"unbalanced group", "(status eq 'open'", ErrCodeInvalidExpression, "expected ) but found end of expression at position 18"},
+		{"trailing input", "status eq 'open' owner", ErrCodeInvalidExpression, `unexpected "owner" at position 18`},
+		{"membership without a list", "tag in 'a'", ErrCodeInvalidExpression, "expected ( but found 'a' at position 8"},
+		{"invalid null", "deleted null 'maybe'", ErrCodeInvalidExpression, "value must be true or false for deleted null at position 21"},
+		{"too deep", "((not (a eq 1)))", ErrCodeExpressionTooDeep, "expression may not be nested more than 3 levels at position 7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, pe := parseExpression(tt.expr, 3)
+			if pe == nil {
+				t.Fatal("parseExpression() expected a ParseError")
+			}
+
+			if pe.Code != tt.wantCode || pe.Parameter != "filter" || pe.Detail != tt.wantDetail {
+				t.Errorf("parseExpression() error = %s %s %q, want %s filter %q", pe.Code, pe.Parameter, pe.Detail, tt.wantCode, tt.wantDetail)
+			}
+		})
+	}
+}
+
+// printer renders an expression in prefix notation
+type printer struct {
+	b strings.Builder
+}
+
+func (p *printer) VisitAnd(e And) error { return p.binary("AND", e.Left, e.Right) }
+
+func (p *printer) VisitOr(e Or) error { return p.binary("OR", e.Left, e.Right) }
+
+func (p *printer) VisitNot(e Not) error {
+	p.b.WriteString("NOT(")
+	if err := e.Expr.Accept(p); err != nil {
+		return err
+	}
+	p.b.WriteString(")")
+
+	return nil
+}
+
+func (p *printer) VisitCompare(e Compare) error {
+	if e.Field == "secret" {
+		return fmt.Errorf("secret may not be compared")
+	}
+
+	fmt.Fprintf(&p.b, "%s:%s:%s", e.Field, e.Operator, strings.Join(e.Values, "|"))
+	return nil
+}
+
+func (p *printer) binary(op string, left Expr, right Expr) error {
+	p.b.WriteString(op + "(")
+	if err := left.Accept(p); err != nil {
+		return err
+	}
+	p.b.WriteString(",")
+	if err := right.Accept(p); err != nil {
+		return err
+	}
+	p.b.WriteString(")")
+
+	return nil
+}
+
+func TestExpr_Accept(t *testing.T) {
+	e, err := ParseExpression("(status eq 'open' or priority gt 3) and not tag in (a, b)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := printer{}
+	if err := e.Accept(&p); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := p.b.String(), "AND(OR(status:eq:open,priority:gt:3),NOT(tag:in:a|b))"; got != want {
+		t.Errorf("Expr.Accept() = %v, want %v", got, want)
+	}
+
+	e, err = ParseExpression("status eq 'open' or secret eq 'x'")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.Accept(&printer{}); err == nil {
+		t.Error("Expr.Accept() expected the visitor error")
+	}
+}
+
+func TestFromQuerystring_expression(t *testing.T) {
+	o, err := FromQuerystring("filter[owner]=me&filter=status%20eq%20'open'%20or%20priority%20gt%203&filter=not%20archived%20eq%20true")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := And{
+		Or{Compare{"status", OpEq, []string{"open"}}, Compare{"priority", OpGt, []string{"3"}}},
+		Not{Compare{"archived", OpEq, []string{"true"}}},
+	}
+	if !reflect.DeepEqual(o.Expression, want) {
+		t.Errorf("FromQuerystring() expression = %#v, want %#v", o.Expression, want)
+	}
+
+	if len(o.Filters) != 1 {
+		t.Errorf("FromQuerystring() filters = %v, want the owner filter", o.Filters)
+	}
+
+	// generated querystrings retain the expression
+	got, err := FromQuerystring(o.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got.Expression, want) {
+		t.Errorf("FromQuerystring(%q) expression = %#v, want %#v", o.String(), got.Expression, want)
+	}
+
+	if _, err := FromQuerystring("filter=status%20eq"); err == nil {
+		t.Error("FromQuerystring() expected an invalid expression error")
+	}
+}
+
+func TestSchema_Validate_expression(t *testing.T) {
+	s := Schema{Fields: map[string]FieldSchema{
+		"status":   {Filterable: true, Operators: []Operator{OpEq, OpIn}},
+		"priority": {Filterable: true},
+	}}
+
+	if _, err := FromQuerystringWithSchema("filter=status eq open or priority gt 3", s); err != nil {
+		t.Errorf("FromQuerystringWithSchema() error = %v", err)
+	}
+
+	_, err := FromQuerystringWithSchema("filter=status ne open or not secret eq 1", s)
+
+	var pes ParseErrors
+	if !errors.As(err, &pes) || len(pes) != 2 || pes[0].Code != ErrCodeOperatorNotPermitted || pes[1].Code != ErrCodeNotFilterable {
+		t.Errorf("FromQuerystringWithSchema() error = %v, want operator not permitted and not filterable", err)
+	}
+}
+
+func TestApply_expression(t *testing.T) {
+	o, err := FromQuerystring("filter[active]=true&filter=(age lt 30 or address.city eq 'Boise') or name eq 'Ann'&sort=id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := Apply(memoryPeople(), o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []int{}
+	for _, p := range got {
+		ids = append(ids, p.ID)
+	}
+
+	if want := []int{1, 4}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Apply() ids = %v, want %v", ids, want)
+	}
+
+	o, err = FromQuerystring("filter=not secret eq 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := Apply(memoryPeople(), o); err == nil {
+		t.Error("Apply() expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,189 @@
+package options
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operator identifies the comparison that a FilterCondition applies
+// to a field
+type Operator string
+
+// Supported filter operators, provided via the querystring as
+// filter[field][operator]=value
+const (
+	OpEq      Operator = "eq"
+	OpNe      Operator = "ne"
+	OpLt      Operator = "lt"
+	OpLte     Operator = "lte"
+	OpGt      Operator = "gt"
+	OpGte     Operator = "gte"
+	OpIn      Operator = "in"
+	OpNin     Operator = "nin"
+	OpLike    Operator = "like"
+	OpNull    Operator = "null"
+	OpBetween Operator = "between"
+)
+
+var operators = []Operator{
+	OpEq,
+	OpNe,
+	OpLt,
+	OpLte,
+	OpGt,
+	OpGte,
+	OpIn,
+	OpNin,
+	OpLike,
+	OpNull,
+	OpBetween,
+}
+
+// prefixes used in the legacy filter[field]=<value form, longest first
+var operatorPrefixes = []struct {
+	prefix string
+	op     Operator
+}{
+	{"<=", OpLte},
+	{">=", OpGte},
+	{"!=", OpNe},
+	{"<", OpLt},
+	{">", OpGt},
+}
+
+// FilterCondition is a typed representation of a single filter
+// provided via the querystring
+type FilterCondition struct {
+	Field    string   `json:"field"`
+	Operator Operator `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+// ParseOperator returns the Operator matching the provided name
+func ParseOperator(name string) (Operator, error) {
+	for _, op := range operators {
+		if strings.EqualFold(string(op), name) {
+			return op, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported filter operator %q", name)
+}
+
+// newFilterCondition creates a FilterCondition for the provided field,
+// explicit operator and values, validating the number of values
+func newFilterCondition(field string, op Operator, values []string) (FilterCondition, *ParseError) {
+	param := fmt.Sprintf("filter[%s][%s]", field, op)
+	value := strings.Join(values, ",")
+
+	switch op {
+	case OpBetween:
+		if len(values) != 2 {
+			return FilterCondition{}, &ParseError{Code: ErrCodeInvalidValue, Parameter: param, Value: value, Detail: "exactly 2 values are required"}
+		}
+	case OpNull:
+		if len(values) != 1 || (values[0] != "true" && values[0] != "false") {
+			return FilterCondition{}, &ParseError{Code: ErrCodeInvalidValue, Parameter: param, Value: value, Detail: "value must be true or false"}
+		}
+	case OpIn, OpNin:
+		// any number of values is acceptable
+	default:
+		if len(values) != 1 {
+			return FilterCondition{}, &ParseError{Code: ErrCodeInvalidValue, Parameter: param, Value: value, Detail: "a single value is required"}
+		}
+	}
+
+	return FilterCondition{field, op, values}, nil
+}
+
+// splitOperatorPrefix separates a comparison prefix (i.e. <=) from
+// the provided value, ok is false when the value has no prefix
+func splitOperatorPrefix(value string) (Operator, string, bool) {
+	for _, p := range operatorPrefixes {
+		if len(value) > len(p.prefix) && strings.HasPrefix(value, p.prefix) {
+			return p.op, value[len(p.prefix):], true
+		}
+	}
+
+	return "", value, false
+}
+
+// legacyConditions creates the conditions for values provided without an
+// operator (as in Options.Filter), where each prefixed value (i.e. <=21) is
+// a comparison and any other values are treated as equality (or membership
+// when there are several)
+func legacyConditions(field string, values []string) []FilterCondition {
+	var (
+		fcs   []FilterCondition
+		plain []string
+	)
+
+	for _, v := range values {
+		if op, cv, ok := splitOperatorPrefix(v); ok {
+			fcs = append(fcs, FilterCondition{field, op, []string{cv}})
+			continue
+		}
+
+		plain = append(plain, v)
+	}
+
+	switch len(plain) {
+	case 0:
+	case 1:
+		fcs = append(fcs, FilterCondition{field, OpEq, plain})
+	default:
+		fcs = append(fcs, FilterCondition{field, OpIn, plain})
+	}
+
+	return fcs
+}
+
+// legacyValues returns the condition values in the form used by
+// Options.Filter, where comparisons are expressed as value prefixes, ok
+// is false when the condition has no such form (i.e. between or null)
+func (fc FilterCondition) legacyValues() ([]string, bool) {
+	var prefix string
+
+	switch fc.Operator {
+	case OpEq, OpIn:
+	case OpNe:
+		prefix = "!="
+	case OpLt:
+		prefix = "<"
+	case OpLte:
+		prefix = "<="
+	case OpGt:
+		prefix = ">"
+	case OpGte:
+		prefix = ">="
+	default:
+		return nil, false
+	}
+
+	values := make([]string, len(fc.Values))
+	for i, v := range fc.Values {
+		values[i] = prefix + v
+
+		// the value must be read back as the same condition (i.e. an
+		// equality value of <5 would be read as a comparison)
+		op, cv, ok := splitOperatorPrefix(values[i])
+		if ok != (prefix != "") || ok && (op != fc.Operator || cv != v) {
+			return nil, false
+		}
+	}
+
+	return values, true
+}
+
+// String returns the querystring representation of the condition
+func (fc FilterCondition) String() string {
+	switch fc.Operator {
+	case OpEq, OpIn:
+		// values that would be read as a comparison require the operator
+		if _, ok := fc.legacyValues(); ok {
+			return fmt.Sprintf("filter[%s]=%s", escapeKey(fc.Field), joinValues(fc.Values))
+		}
+	}
+
+	return fmt.Sprintf("filter[%s][%s]=%s", escapeKey(fc.Field), fc.Operator, joinValues(fc.Values))
+}
@@ -0,0 +1,111 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterCondition_String(t *testing.T) {
+	tests := []struct {
+		name string
+		fc   FilterCondition
+		want string
+	}{
+		{"equality", FilterCondition{"fieldA", OpEq, []string{"value"}}, "filter[fieldA]=value"},
+		{"membership", FilterCondition{"fieldA", OpIn, []string{"value1", "value2"}}, "filter[fieldA]=value1,value2"},
+		{"comparison", FilterCondition{"age", OpGte, []string{"21"}}, "filter[age][gte]=21"},
+		{"range", FilterCondition{"age", OpBetween, []string{"21", "65"}}, "filter[age][between]=21,65"},
+		{"equality with a prefixed value", FilterCondition{"a", OpEq, []string{"<5"}}, "filter[a][eq]=%3C5"},
+		{"membership with a prefixed value", FilterCondition{"a", OpIn, []string{"x", "!=y"}}, "filter[a][in]=x,%21%3Dy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fc.String(); got != tt.want {
+				t.Errorf("FilterCondition.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newFilterCondition(t *testing.T) {
+	type args struct {
+		field  string
+		op     Operator
+		values []string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    FilterCondition
+		wantErr bool
+	}{
+		{"explicit operator", args{"a", OpLike, []string{"te*"}}, FilterCondition{"a", OpLike, []string{"te*"}}, false},
+		{"explicit operator with multiple values", args{"a", OpGt, []string{"1", "2"}}, FilterCondition{}, true},
+		{"null with a non-boolean value", args{"a", OpNull, []string{"yes"}}, FilterCondition{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newFilterCondition(tt.args.field, tt.args.op, tt.args.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newFilterCondition() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newFilterCondition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_legacyConditions(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []FilterCondition
+	}{
+		{"single value", []string{"1"}, []FilterCondition{{"a", OpEq, []string{"1"}}}},
+		{"multiple values", []string{"1", "2"}, []FilterCondition{{"a", OpIn, []string{"1", "2"}}}},
+		{"not equal prefix", []string{"!=1"}, []FilterCondition{{"a", OpNe, []string{"1"}}}},
+		{"prefix without a value", []string{"<"}, []FilterCondition{{"a", OpEq, []string{"<"}}}},
+		{"comparisons and a value", []string{">=21", "<65", "30"}, []FilterCondition{
+			{"a", OpGte, []string{"21"}},
+			{"a", OpLt, []string{"65"}},
+			{"a", OpEq, []string{"30"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := legacyConditions("a", tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("legacyConditions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterCondition_legacyValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		fc     FilterCondition
+		want   []string
+		wantOK bool
+	}{
+		{"equality", FilterCondition{"a", OpEq, []string{"1"}}, []string{"1"}, true},
+		{"membership", FilterCondition{"a", OpIn, []string{"1", "2"}}, []string{"1", "2"}, true},
+		{"comparison", FilterCondition{"a", OpGte, []string{"21"}}, []string{">=21"}, true},
+		{"equality with a prefixed value", FilterCondition{"a", OpEq, []string{"<5"}}, nil, false},
+		{"comparison that reads as another", FilterCondition{"a", OpLt, []string{"=5"}}, nil, false},
+		{"between", FilterCondition{"a", OpBetween, []string{"1", "2"}}, nil, false},
+		{"like", FilterCondition{"a", OpLike, []string{"te*"}}, nil, false},
+		{"null", FilterCondition{"a", OpNull, []string{"true"}}, nil, false},
+		{"exclusion", FilterCondition{"a", OpNin, []string{"1"}}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.fc.legacyValues()
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterCondition.legacyValues() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
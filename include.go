@@ -0,0 +1,107 @@
+package options
+
+import "strings"
+
+// Relationship is a node in the tree of relationship paths provided
+// via the include parameter (i.e. include=author,comments.author)
+type Relationship struct {
+	Name          string         `json:"name"`
+	Relationships []Relationship `json:"relationships,omitempty"`
+}
+
+// IncludePaths returns the dot-separated relationship paths to include,
+// omitting any path that is implied by a longer path (i.e. comments is
+// implied by comments.author)
+func (o Options) IncludePaths() []string {
+	return includePaths(o.Include, "")
+}
+
+// ContainsInclude confirms whether or not the requested dot-separated
+// relationship path is included, either explicitly or as part of
+// a longer path
+func (o Options) ContainsInclude(path string) bool {
+	if path == "" {
+		return false
+	}
+
+	rels := o.Include
+	for _, name := range strings.Split(path, ".") {
+		i := indexRelationship(rels, name)
+		if i < 0 {
+			return false
+		}
+
+		rels = rels[i].Relationships
+	}
+
+	return true
+}
+
+func addIncludePath(rels []Relationship, names []string) []Relationship {
+	if len(names) == 0 {
+		return rels
+	}
+
+	i := indexRelationship(rels, names[0])
+	if i < 0 {
+		rels = append(rels, Relationship{Name: names[0]})
+		i = len(rels) - 1
+	}
+
+	rels[i].Relationships = addIncludePath(rels[i].Relationships, names[1:])
+
+	return rels
+}
+
+func includePaths(rels []Relationship, prefix string) []string {
+	var paths []string
+
+	for _, r := range rels {
+		path := prefix + r.Name
+		if len(r.Relationships) == 0 {
+			paths = append(paths, path)
+			continue
+		}
+
+		paths = append(paths, includePaths(r.Relationships, path+".")...)
+	}
+
+	return paths
+}
+
+func indexRelationship(rels []Relationship, name string) int {
+	for i, r := range rels {
+		if r.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parseIncludePaths adds each comma separated relationship path provided
+// in an include parameter value to the relationship tree
+func parseIncludePaths(rels []Relationship, value string) ([]Relationship, *ParseError) {
+	paths, err := splitNames("include", value)
+	if err != nil {
+		return rels, err
+	}
+
+	for _, path := range paths {
+		names := strings.Split(path, ".")
+		for _, name := range names {
+			if name == "" {
+				return rels, &ParseError{
+					Code:      ErrCodeInvalidValue,
+					Parameter: "include",
+					Value:     path,
+					Detail:    "relationship path is not valid",
+				}
+			}
+		}
+
+		rels = addIncludePath(rels, names)
+	}
+
+	return rels, nil
+}
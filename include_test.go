@@ -0,0 +1,126 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromQuerystring_include(t *testing.T) {
+	tests := []struct {
+		name      string
+		qs        string
+		want      []Relationship
+		wantPaths []string
+		wantErr   bool
+	}{
+		{"no include", "sort=name", nil, nil, false},
+		{
+			"single relationship",
+			"include=author",
+			[]Relationship{{Name: "author"}},
+			[]string{"author"},
+			false,
+		},
+		{
+			"nested relationships",
+			"include=author,comments.author",
+			[]Relationship{
+				{Name: "author"},
+				{Name: "comments", Relationships: []Relationship{{Name: "author"}}},
+			},
+			[]string{"author", "comments.author"},
+			false,
+		},
+		{
+			"duplicate and implied relationships",
+			"include=comments,comments.author&include=comments.author,author",
+			[]Relationship{
+				{Name: "comments", Relationships: []Relationship{{Name: "author"}}},
+				{Name: "author"},
+			},
+			[]string{"comments.author", "author"},
+			false,
+		},
+		{"empty relationship name", "include=comments..author", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromQuerystring(tt.qs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FromQuerystring() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got.Include, tt.want) {
+				t.Errorf("FromQuerystring() include = %+v, want %+v", got.Include, tt.want)
+			}
+
+			if paths := got.IncludePaths(); !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("Options.IncludePaths() = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestOptions_ContainsInclude(t *testing.T) {
+	o, err := FromQuerystring("include=comments.author")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]bool{
+		"comments":        true,
+		"comments.author": true,
+		"author":          false,
+		"":                false,
+	} {
+		if got := o.ContainsInclude(path); got != want {
+			t.Errorf("Options.ContainsInclude(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestOptions_Next_include(t *testing.T) {
+	o, err := FromQuerystring("include=author,comments.author&page[limit]=10&page[offset]=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "include=author,comments.author&page[limit]=10&page[offset]=10"
+	if got := o.Next(); got != want {
+		t.Errorf("Options.Next() = %v, want %v", got, want)
+	}
+}
+
+func TestSchema_Validate_include(t *testing.T) {
+	s := Schema{
+		MaxIncludeDepth: 2,
+		Relationships:   []string{"author", "comments.author.company"},
+	}
+
+	tests := []struct {
+		name     string
+		qs       string
+		wantCode []ErrorCode
+	}{
+		{"permitted relationships", "include=author,comments.author", nil},
+		{"unknown relationship", "include=editor", []ErrorCode{ErrCodeNotIncludable}},
+		{"relationship path too deep", "include=comments.author.company", []ErrorCode{ErrCodeIncludeTooDeep}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromQuerystringWithSchema(tt.qs, s)
+
+			var codes []ErrorCode
+			if pes, ok := err.(ParseErrors); ok {
+				for _, pe := range pes {
+					codes = append(codes, pe.Code)
+				}
+			}
+
+			if !reflect.DeepEqual(codes, tt.wantCode) {
+				t.Errorf("FromQuerystringWithSchema() error = %v, want codes %v", err, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// LinkHeader returns an RFC 8288 Link header value (i.e.
+// <https://api.example.com/people?page[limit]=10&page[offset]=10>; rel="next")
+// containing the first, prev, next and last links for the provided base
+// URL and total number of items, links that are not available are omitted
+func (o Options) LinkHeader(base string, total int) (string, error) {
+	doc, err := o.PageDocument(base, total)
+	if err != nil {
+		return "", err
+	}
+
+	links := []struct {
+		rel  string
+		href string
+	}{
+		{"first", doc.Links.First},
+		{"prev", doc.Links.Prev},
+		{"next", doc.Links.Next},
+		{"last", doc.Links.Last},
+	}
+
+	values := []string{}
+	for _, l := range links {
+		if l.href != "" {
+			values = append(values, fmt.Sprintf("<%s>; rel=%q", l.href, l.rel))
+		}
+	}
+
+	return strings.Join(values, ", "), nil
+}
+
+// ParseLinkHeader reads an RFC 8288 Link header value and returns the
+// Options of each link keyed by relation type (i.e. next)
+func ParseLinkHeader(header string) (map[string]Options, error) {
+	links := map[string]Options{}
+
+	for rest := strings.TrimSpace(header); rest != ""; {
+		if rest[0] != '<' {
+			return nil, fmt.Errorf("invalid link header: expected < at %q", rest)
+		}
+
+		end := strings.IndexByte(rest, '>')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid link header: missing > in %q", rest)
+		}
+
+		href := rest[1:end]
+		params, remainder := cutLinkParams(rest[end+1:])
+		rest = strings.TrimSpace(remainder)
+
+		u, err := url.Parse(href)
+		if err != nil {
+			return nil, fmt.Errorf("invalid link header: %w", err)
+		}
+
+		o, err := FromQuerystring(u.RawQuery)
+		if err != nil {
+			return nil, err
+		}
+
+		// a link may have multiple relation types (i.e. rel="next last")
+		for _, rel := range strings.Fields(params["rel"]) {
+			links[strings.ToLower(rel)] = o
+		}
+	}
+
+	return links, nil
+}
+
+// cutLinkParams reads the parameters of a single link (i.e. ; rel="next")
+// up to the comma separating it from the next link, and returns them along
+// with the remainder of the header
+func cutLinkParams(s string) (map[string]string, string) {
+	params := map[string]string{}
+
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return params, ""
+		}
+
+		if s[0] == ',' {
+			return params, s[1:]
+		}
+
+		if s[0] != ';' {
+			// skip unexpected characters
+			s = s[1:]
+			continue
+		}
+
+		s = strings.TrimLeft(s[1:], " \t")
+
+		// parameter name
+		i := strings.IndexAny(s, "=;,")
+		if i < 0 {
+			i = len(s)
+		}
+
+		name := strings.ToLower(strings.TrimSpace(s[:i]))
+		s = s[i:]
+		if s == "" || s[0] != '=' {
+			params[name] = ""
+			continue
+		}
+
+		s = strings.TrimLeft(s[1:], " \t")
+
+		// parameter value, which may be quoted
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			b := strings.Builder{}
+			i = 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+
+				b.WriteByte(s[i])
+			}
+
+			value = b.String()
+			s = s[min(i+1, len(s)):]
+		} else {
+			i = strings.IndexAny(s, ";,")
+			if i < 0 {
+				i = len(s)
+			}
+
+			value = strings.TrimSpace(s[:i])
+			s = s[i:]
+		}
+
+		params[name] = value
+	}
+}
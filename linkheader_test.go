@@ -0,0 +1,151 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptions_LinkHeader(t *testing.T) {
+	tests := []struct {
+		name  string
+		qs    string
+		total int
+		want  string
+	}{
+		{
+			"middle page",
+			"page[limit]=10&page[offset]=10",
+			95,
+			`</people?page[limit]=10&page[offset]=0>; rel="first", ` +
+				`</people?page[limit]=10&page[offset]=0>; rel="prev", ` +
+				`</people?page[limit]=10&page[offset]=20>; rel="next", ` +
+				`</people?page[limit]=10&page[offset]=90>; rel="last"`,
+		},
+		{
+			"last page",
+			"page[number]=4&page[size]=25",
+			100,
+			`</people?page[number]=1&page[size]=25>; rel="first", ` +
+				`</people?page[number]=3&page[size]=25>; rel="prev", ` +
+				`</people?page[number]=4&page[size]=25>; rel="last"`,
+		},
+		{
+			"not paginated",
+			"sort=name",
+			100,
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := o.LinkHeader("/people", tt.total)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Options.LinkHeader() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantPage map[string]map[string]int
+		wantErr  bool
+	}{
+		{
+			"generated header",
+			`</people?page[limit]=10&page[offset]=0>; rel="first", </people?page[limit]=10&page[offset]=20>; rel="next"`,
+			map[string]map[string]int{
+				"first": {"limit": 10, "offset": 0},
+				"next":  {"limit": 10, "offset": 20},
+			},
+			false,
+		},
+		{
+			"GitHub style header",
+			`<https://api.github.com/repositories/1/issues?page=2&per_page=30>; rel="next", <https://api.github.com/repositories/1/issues?page=5&per_page=30>; rel="last"`,
+			map[string]map[string]int{
+				"next": {},
+				"last": {},
+			},
+			false,
+		},
+		{
+			"commas within the URL and multiple relation types",
+			`</people?fields=name,age&page[size]=10&page[page]=3>; title="a, b"; rel="next last"`,
+			map[string]map[string]int{
+				"next": {"size": 10, "page": 3},
+				"last": {"size": 10, "page": 3},
+			},
+			false,
+		},
+		{
+			"unquoted relation type",
+			`</people?page[limit]=5>; rel=Prev`,
+			map[string]map[string]int{
+				"prev": {"limit": 5},
+			},
+			false,
+		},
+		{"empty header", "", map[string]map[string]int{}, false},
+		{"missing brackets", `/people; rel="next"`, nil, true},
+		{"unterminated URL", `</people; rel="next"`, nil, true},
+		{"invalid page value", `</people?page[limit]=ten>; rel="next"`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLinkHeader(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLinkHeader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			pages := map[string]map[string]int{}
+			for rel, o := range got {
+				pages[rel] = o.Page
+			}
+
+			if !reflect.DeepEqual(pages, tt.wantPage) {
+				t.Errorf("ParseLinkHeader() pages = %v, want %v", pages, tt.wantPage)
+			}
+		})
+	}
+}
+
+func TestParseLinkHeader_roundTrip(t *testing.T) {
+	o, err := FromQuerystring("filter[status]=open&page[limit]=10&page[offset]=10&sort=-created")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := o.LinkHeader("https://api.example.com/issues", 35)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	links, err := ParseLinkHeader(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := links["next"].String(), o.Next(); got != want {
+		t.Errorf("ParseLinkHeader() next = %v, want %v", got, want)
+	}
+
+	if got, want := links["last"].String(), o.Last(35); got != want {
+		t.Errorf("ParseLinkHeader() last = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package options
+
+import "net/url"
+
+// PageDocument contains the top-level links and meta members of a
+// paginated JSON:API collection document
+type PageDocument struct {
+	Links PageLinks `json:"links"`
+	Meta  PageMeta  `json:"meta"`
+}
+
+// PageLinks contains absolute URLs for the current page of a collection,
+// prev and next are empty when there is no page in that direction, first
+// and last are empty when the collection is not paginated
+type PageLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// PageMeta contains the page member of a collection's meta object
+type PageMeta struct {
+	Page PageInfo `json:"page"`
+}
+
+// PageDocument returns the links and meta members for the current page
+// using the provided base URL (i.e. https://api.example.com/articles)
+// and total number of items, any querystring of the base URL is replaced
+//
+// Cursor pagination does not have a total, see CursorPagination.Page
+func (o Options) PageDocument(base string, total int) (PageDocument, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return PageDocument{}, err
+	}
+
+	doc := PageDocument{
+		Links: PageLinks{Self: link(u, o.String())},
+		Meta:  PageMeta{Page: o.PageInfo(total)},
+	}
+
+	// first and last are only meaningful when paginating
+	if o.ps == nil || len(o.Page) == 0 || o.ps.Last(o.Page, total) == "" {
+		return doc, nil
+	}
+
+	doc.Links.First = link(u, o.First())
+	doc.Links.Last = link(u, o.Last(total))
+
+	if qs, ok := o.PrevPage(total); ok {
+		doc.Links.Prev = link(u, qs)
+	}
+
+	if qs, ok := o.NextPage(total); ok {
+		doc.Links.Next = link(u, qs)
+	}
+
+	return doc, nil
+}
+
+// link returns the base URL with the provided querystring
+func link(base *url.URL, qs string) string {
+	u := *base
+	u.RawQuery = qs
+	u.ForceQuery = false
+
+	return u.String()
+}
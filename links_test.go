@@ -0,0 +1,118 @@
+package options
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOptions_PageDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		qs      string
+		base    string
+		total   int
+		want    PageLinks
+		wantErr bool
+	}{
+		{
+			"middle page",
+			"page[limit]=10&page[offset]=10&sort=name",
+			"https://api.example.com/people",
+			95,
+			PageLinks{
+				Self:  "https://api.example.com/people?page[limit]=10&page[offset]=10&sort=name",
+				First: "https://api.example.com/people?page[limit]=10&page[offset]=0&sort=name",
+				Prev:  "https://api.example.com/people?page[limit]=10&page[offset]=0&sort=name",
+				Next:  "https://api.example.com/people?page[limit]=10&page[offset]=20&sort=name",
+				Last:  "https://api.example.com/people?page[limit]=10&page[offset]=90&sort=name",
+			},
+			false,
+		},
+		{
+			"first page",
+			"page[number]=1&page[size]=25",
+			"https://api.example.com/people?ignored=true",
+			60,
+			PageLinks{
+				Self:  "https://api.example.com/people?page[number]=1&page[size]=25",
+				First: "https://api.example.com/people?page[number]=1&page[size]=25",
+				Next:  "https://api.example.com/people?page[number]=2&page[size]=25",
+				Last:  "https://api.example.com/people?page[number]=3&page[size]=25",
+			},
+			false,
+		},
+		{
+			"last page",
+			"page[size]=25&page[page]=2",
+			"/people",
+			60,
+			PageLinks{
+				Self:  "/people?page[size]=25&page[page]=2",
+				First: "/people?page[size]=25&page[page]=0",
+				Prev:  "/people?page[size]=25&page[page]=1",
+				Last:  "/people?page[size]=25&page[page]=2",
+			},
+			false,
+		},
+		{
+			"not paginated",
+			"",
+			"https://api.example.com/people",
+			3,
+			PageLinks{Self: "https://api.example.com/people"},
+			false,
+		},
+		{
+			"invalid base URL",
+			"",
+			"://",
+			3,
+			PageLinks{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := o.PageDocument(tt.base, tt.total)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Options.PageDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got.Links, tt.want) {
+				t.Errorf("Options.PageDocument() links = %+v, want %+v", got.Links, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageDocument_json(t *testing.T) {
+	o, err := FromQuerystring("page[limit]=10&page[offset]=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := o.PageDocument("/people", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := strings.Builder{}
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"links":{"self":"/people?page[limit]=10&page[offset]=0","first":"/people?page[limit]=10&page[offset]=0","last":"/people?page[limit]=10&page[offset]=0"},` +
+		`"meta":{"page":{"page":1,"totalPages":1,"total":5,"limit":10,"offset":0,"firstItem":1,"lastItem":5,"hasNext":false,"hasPrev":false}}}`
+	if got := strings.TrimSpace(b.String()); got != want {
+		t.Errorf("json.Encode() = %s, want %s", got, want)
+	}
+}
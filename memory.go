@@ -0,0 +1,647 @@
+package options
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Apply filters, sorts and paginates the provided items in memory, and
+// when fields are provided, projects each item to the selected fields
+//
+// Field names are resolved via the json tag of struct fields (falling back
+// to the Go field name), including those promoted from embedded structs,
+// or the keys of maps, and dot-separated names are resolved through nested
+// structs and maps. Values are compared according
+// to the type of the field (strings, numbers, booleans and time.Time, for
+// which RFC 3339 values are expected).
+//
+// The returned PageInfo describes the page relative to the number of items
+// matching the filters. When the pagination strategy does not implement
+// ILimitOffsetStrategy, all matching items are returned. Cursor pagination
+// is not supported as the cursors of the items are not known, a ParseError
+// is returned instead.
+func Apply[T any](items []T, o Options) ([]T, PageInfo, error) {
+	if err := cursorNotSupported(o); err != nil {
+		return nil, PageInfo{}, err
+	}
+
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	fcs := o.Conditions()
+
+	// fields of the filter expression are validated along with the filters
+	all := append(append([]FilterCondition{}, fcs...), exprConditions(o.Expression)...)
+	if err := validateMemoryFields(typ, o, all); err != nil {
+		return nil, PageInfo{}, err
+	}
+
+	// filters
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		ok, err := matchConditions(reflect.ValueOf(item), fcs)
+		if err == nil && ok && o.Expression != nil {
+			ok, err = matchExpr(reflect.ValueOf(item), o.Expression)
+		}
+
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+
+		if ok {
+			matched = append(matched, item)
+		}
+	}
+
+	// sorting
+	if len(o.Sort) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			a := reflect.ValueOf(matched[i])
+			b := reflect.ValueOf(matched[j])
+
+			for _, field := range o.Sort {
+				name := strings.TrimLeft(field, "-+")
+				c := compareValues(lookupValue(a, name), lookupValue(b, name))
+				if c == 0 {
+					continue
+				}
+
+				if strings.HasPrefix(field, "-") {
+					return c > 0
+				}
+
+				return c < 0
+			}
+
+			return false
+		})
+	}
+
+	// pagination
+	pi := o.PageInfo(len(matched))
+	start := min(pi.Offset, len(matched))
+	end := min(start+pi.Limit, len(matched))
+	page := matched[start:end]
+
+	// field projections
+	if len(o.Fields) > 0 {
+		projected := make([]T, len(page))
+		for i, item := range page {
+			// nil items (i.e. of an interface type) are not projected
+			v := reflect.ValueOf(item)
+			if !v.IsValid() {
+				projected[i] = item
+				continue
+			}
+
+			projected[i] = projectValue(v, o.Fields).Interface().(T)
+		}
+
+		return projected, pi, nil
+	}
+
+	return page, pi, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// cursorNotSupported returns a ParseError naming the cursor parameter when
+// the Options use cursor pagination
+func cursorNotSupported(o Options) error {
+	_, ok := o.ps.(*CursorStrategy)
+	if !ok && len(o.Cursor) == 0 {
+		return nil
+	}
+
+	param, value := "page[size]", ""
+	for _, term := range []string{"after", "before"} {
+		if v, ok := o.Cursor[term]; ok {
+			param, value = fmt.Sprintf("page[%s]", term), v
+			break
+		}
+	}
+
+	return ParseErrors{{
+		Code:      ErrCodeInvalidValue,
+		Parameter: param,
+		Value:     value,
+		Detail:    "cursor pagination is not supported",
+	}}
+}
+
+// validateMemoryFields confirms that the filter, sort and fields exist
+// when the items are structs, fields of maps are not known in advance
+func validateMemoryFields(typ reflect.Type, o Options, fcs []FilterCondition) error {
+	var errs ParseErrors
+
+	for _, fc := range fcs {
+		if !typeHasField(typ, fc.Field) {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeNotFilterable,
+				Parameter: fmt.Sprintf("filter[%s]", fc.Field),
+				Detail:    fmt.Sprintf("%q is not a field", fc.Field),
+			})
+		}
+	}
+
+	for _, field := range o.Sort {
+		name := strings.TrimLeft(field, "-+")
+		if !typeHasField(typ, name) {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeNotSortable,
+				Parameter: "sort",
+				Value:     field,
+				Detail:    fmt.Sprintf("%q is not a field", name),
+			})
+		}
+	}
+
+	for _, field := range o.Fields {
+		if !typeHasField(typ, field) {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeNotSelectable,
+				Parameter: "fields",
+				Value:     field,
+				Detail:    fmt.Sprintf("%q is not a field", field),
+			})
+		}
+	}
+
+	return errs.errs()
+}
+
+// typeHasField reports whether the dot-separated field may exist on the
+// provided type, which is only known for structs
+func typeHasField(typ reflect.Type, path string) bool {
+	for _, name := range strings.Split(path, ".") {
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		if typ.Kind() != reflect.Struct || typ == timeType {
+			return typ.Kind() == reflect.Map || typ.Kind() == reflect.Interface
+		}
+
+		sf, ok := structField(typ, name)
+		if !ok {
+			return false
+		}
+
+		typ = sf.Type
+	}
+
+	return true
+}
+
+// structField finds the exported field of the struct type for the
+// provided name, preferring the json tag name, including the fields
+// promoted from embedded structs as resolved by encoding/json
+func structField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	current := []reflect.StructField{{Type: typ}}
+	visited := map[reflect.Type]bool{}
+
+	for len(current) > 0 {
+		var next, found []reflect.StructField
+
+		for _, embedded := range current {
+			et := embedded.Type
+			if et.Kind() == reflect.Pointer {
+				et = et.Elem()
+			}
+
+			if visited[et] {
+				continue
+			}
+
+			for i := 0; i < et.NumField(); i++ {
+				sf := et.Field(i)
+
+				tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+				if tag == "-" {
+					continue
+				}
+
+				ft := sf.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+
+				// fields of unexported embedded structs are promoted
+				if !sf.IsExported() && !(sf.Anonymous && ft.Kind() == reflect.Struct) {
+					continue
+				}
+
+				sf.Index = append(append([]int{}, embedded.Index...), i)
+
+				if sf.Anonymous && tag == "" && ft.Kind() == reflect.Struct {
+					next = append(next, sf)
+					continue
+				}
+
+				if sf.IsExported() && (tag == name || tag == "" && sf.Name == name) {
+					found = append(found, sf)
+				}
+			}
+		}
+
+		// the shallowest fields with the name hide any that are deeper
+		if len(found) > 0 {
+			return dominantField(found)
+		}
+
+		for _, embedded := range current {
+			visited[embedded.Type] = true
+		}
+
+		current = next
+	}
+
+	return reflect.StructField{}, false
+}
+
+// dominantField returns the field that encoding/json uses among fields
+// of the same name and depth, a single tagged field or the only field,
+// otherwise the name is ambiguous and none is used
+func dominantField(fields []reflect.StructField) (reflect.StructField, bool) {
+	var tagged []reflect.StructField
+	for _, sf := range fields {
+		if tag, _, _ := strings.Cut(sf.Tag.Get("json"), ","); tag != "" {
+			tagged = append(tagged, sf)
+		}
+	}
+
+	switch {
+	case len(tagged) == 1:
+		return tagged[0], true
+	case len(tagged) == 0 && len(fields) == 1:
+		return fields[0], true
+	}
+
+	return reflect.StructField{}, false
+}
+
+// fieldByIndex returns the nested field of the struct value, or an
+// invalid Value when an embedded struct pointer is nil
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	fv, err := v.FieldByIndexErr(index)
+	if err != nil {
+		return reflect.Value{}
+	}
+
+	return fv
+}
+
+// lookupValue resolves the dot-separated field of the provided value,
+// returning an invalid Value when the field is not present or is nil
+func lookupValue(v reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		v = indirect(v)
+
+		switch {
+		case !v.IsValid():
+			return v
+		case v.Kind() == reflect.Struct:
+			sf, ok := structField(v.Type(), name)
+			if !ok {
+				return reflect.Value{}
+			}
+
+			v = fieldByIndex(v, sf.Index)
+		case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		default:
+			return reflect.Value{}
+		}
+	}
+
+	return indirect(v)
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid
+// Value when nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func matchConditions(item reflect.Value, fcs []FilterCondition) (bool, error) {
+	for _, fc := range fcs {
+		ok, err := matchCondition(lookupValue(item, fc.Field), fc)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchExpr(item reflect.Value, e Expr) (bool, error) {
+	switch e := e.(type) {
+	case And:
+		ok, err := matchExpr(item, e.Left)
+		if err != nil || !ok {
+			return false, err
+		}
+
+		return matchExpr(item, e.Right)
+	case Or:
+		ok, err := matchExpr(item, e.Left)
+		if err != nil || ok {
+			return ok, err
+		}
+
+		return matchExpr(item, e.Right)
+	case Not:
+		ok, err := matchExpr(item, e.Expr)
+		return !ok, err
+	case Compare:
+		return matchCondition(lookupValue(item, e.Field), e.Condition())
+	}
+
+	return false, fmt.Errorf("unsupported expression %T", e)
+}
+
+func matchCondition(v reflect.Value, fc FilterCondition) (bool, error) {
+	if fc.Operator == OpNull {
+		return !v.IsValid() == (fc.Values[0] == "true"), nil
+	}
+
+	// nil values only match negated conditions
+	if !v.IsValid() {
+		return fc.Operator == OpNe || fc.Operator == OpNin, nil
+	}
+
+	compare := func(value string) (int, error) {
+		c, err := compareString(v, value)
+		if err != nil {
+			return 0, &ParseError{
+				Code:      ErrCodeInvalidValue,
+				Parameter: fmt.Sprintf("filter[%s]", fc.Field),
+				Value:     value,
+				Detail:    err.Error(),
+				Err:       err,
+			}
+		}
+
+		return c, nil
+	}
+
+	switch fc.Operator {
+	case OpEq, OpIn, OpNe, OpNin:
+		found := false
+		for _, value := range fc.Values {
+			c, err := compare(value)
+			if err != nil {
+				return false, err
+			}
+
+			if c == 0 {
+				found = true
+				break
+			}
+		}
+
+		return found == (fc.Operator == OpEq || fc.Operator == OpIn), nil
+	case OpLike:
+		return matchWildcard(fc.Values[0], fmt.Sprint(v.Interface())), nil
+	case OpBetween:
+		lo, err := compare(fc.Values[0])
+		if err != nil {
+			return false, err
+		}
+
+		hi, err := compare(fc.Values[1])
+		if err != nil {
+			return false, err
+		}
+
+		return lo >= 0 && hi <= 0, nil
+	}
+
+	c, err := compare(fc.Values[0])
+	if err != nil {
+		return false, err
+	}
+
+	switch fc.Operator {
+	case OpLt:
+		return c < 0, nil
+	case OpLte:
+		return c <= 0, nil
+	case OpGt:
+		return c > 0, nil
+	case OpGte:
+		return c >= 0, nil
+	}
+
+	return false, fmt.Errorf("unsupported filter operator %q", fc.Operator)
+}
+
+// compareString compares the value with the provided querystring value,
+// parsed according to the type of the value
+func compareString(v reflect.Value, s string) (int, error) {
+	if v.Type() == timeType {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return 0, fmt.Errorf("value must be an RFC 3339 time")
+		}
+
+		return v.Interface().(time.Time).Compare(t), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value must be an integer")
+		}
+
+		return cmp.Compare(v.Int(), i), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value must be a non-negative integer")
+		}
+
+		return cmp.Compare(v.Uint(), u), nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value must be a number")
+		}
+
+		return cmp.Compare(v.Float(), f), nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return 0, fmt.Errorf("value must be true or false")
+		}
+
+		return compareBool(v.Bool(), b), nil
+	case reflect.String:
+		return strings.Compare(v.String(), s), nil
+	}
+
+	return strings.Compare(fmt.Sprint(v.Interface()), s), nil
+}
+
+// compareValues orders two field values, where invalid (nil) values are
+// ordered first
+func compareValues(a, b reflect.Value) int {
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return 0
+	case !a.IsValid():
+		return -1
+	case !b.IsValid():
+		return 1
+	}
+
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time))
+	}
+
+	switch {
+	case a.CanInt() && b.CanInt():
+		return cmp.Compare(a.Int(), b.Int())
+	case a.CanUint() && b.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint())
+	case a.CanFloat() && b.CanFloat():
+		return cmp.Compare(a.Float(), b.Float())
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		return compareBool(a.Bool(), b.Bool())
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String())
+	}
+
+	return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// matchWildcard reports whether the value matches the pattern, where *
+// matches any sequence of characters
+func matchWildcard(pattern string, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+
+		value = value[i+len(part):]
+	}
+
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
+
+// allocField returns the nested field of the struct value, allocating
+// the embedded struct pointers leading to it, or an invalid Value when
+// one of them can not be set (i.e. an unexported embedded pointer)
+func allocField(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// projectValue returns a copy of the provided struct or map containing
+// only the selected fields, other values are returned unchanged
+func projectValue(v reflect.Value, fields []string) reflect.Value {
+	selected := map[string]bool{}
+	for _, field := range fields {
+		name, _, _ := strings.Cut(field, ".")
+		selected[name] = true
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+
+		p := reflect.New(v.Elem().Type())
+		p.Elem().Set(projectValue(v.Elem(), fields))
+
+		return p
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		return projectValue(v.Elem(), fields)
+	case reflect.Struct:
+		s := reflect.New(v.Type()).Elem()
+		for name := range selected {
+			sf, ok := structField(v.Type(), name)
+			if !ok {
+				continue
+			}
+
+			if fv := fieldByIndex(v, sf.Index); fv.IsValid() {
+				if dst := allocField(s, sf.Index); dst.IsValid() {
+					dst.Set(fv)
+				}
+			}
+		}
+
+		return s
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v
+		}
+
+		m := reflect.MakeMapWithSize(v.Type(), len(selected))
+		for name := range selected {
+			key := reflect.ValueOf(name).Convert(v.Type().Key())
+			if mv := v.MapIndex(key); mv.IsValid() {
+				m.SetMapIndex(key, mv)
+			}
+		}
+
+		return m
+	}
+
+	return v
+}
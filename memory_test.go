@@ -0,0 +1,295 @@
+package options
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type memoryPerson struct {
+	ID      int       `json:"id"`
+	Name    string    `json:"name"`
+	Age     int       `json:"age"`
+	Active  bool      `json:"active"`
+	Email   *string   `json:"email,omitempty"`
+	Joined  time.Time `json:"joined"`
+	Address struct {
+		City string `json:"city"`
+	} `json:"address"`
+	Score float64
+}
+
+func memoryPeople() []memoryPerson {
+	email := "ann@example.com"
+	people := []memoryPerson{
+		{ID: 1, Name: "Ann", Age: 34, Active: true, Email: &email, Joined: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Score: 9.5},
+		{ID: 2, Name: "Bob", Age: 21, Active: false, Joined: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), Score: 7},
+		{ID: 3, Name: "Cy", Age: 34, Active: true, Joined: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), Score: 8.25},
+		{ID: 4, Name: "Dee", Age: 58, Active: true, Joined: time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC), Score: 6},
+	}
+	people[0].Address.City = "Seattle"
+	people[1].Address.City = "Portland"
+	people[2].Address.City = "Seattle"
+	people[3].Address.City = "Boise"
+
+	return people
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		qs       string
+		wantIDs  []int
+		wantInfo PageInfo
+	}{
+		{"no options", "", []int{1, 2, 3, 4}, PageInfo{Page: 1, TotalPages: 1, Total: 4, Limit: 4, FirstItem: 1, LastItem: 4}},
+		{"equality", "filter[name]=Bob", []int{2}, PageInfo{Page: 1, TotalPages: 1, Total: 1, Limit: 1, FirstItem: 1, LastItem: 1}},
+		{"membership", "filter[name]=Ann,Dee", []int{1, 4}, PageInfo{Page: 1, TotalPages: 1, Total: 2, Limit: 2, FirstItem: 1, LastItem: 2}},
+		{"not in", "filter[name][nin]=Ann,Dee", []int{2, 3}, PageInfo{Page: 1, TotalPages: 1, Total: 2, Limit: 2, FirstItem: 1, LastItem: 2}},
+		{"comparison", "filter[age][gte]=34", []int{1, 3, 4}, PageInfo{Page: 1, TotalPages: 1, Total: 3, Limit: 3, FirstItem: 1, LastItem: 3}},
+		{"legacy comparison prefix", "filter[age]=<30", []int{2}, PageInfo{Page: 1, TotalPages: 1, Total: 1, Limit: 1, FirstItem: 1, LastItem: 1}},
+		{"between", "filter[age][between]=30,40", []int{1, 3}, PageInfo{Page: 1, TotalPages: 1, Total: 2, Limit: 2, FirstItem: 1, LastItem: 2}},
+		{"boolean", "filter[active]=false", []int{2}, PageInfo{Page: 1, TotalPages: 1, Total: 1, Limit: 1, FirstItem: 1, LastItem: 1}},
+		{"time", "filter[joined][gt]=2020-06-01T00:00:00Z", []int{2, 4}, PageInfo{Page: 1, TotalPages: 1, Total: 2, Limit: 2, FirstItem: 1, LastItem: 2}},
+		{"float without a tag", "filter[Score][lt]=8", []int{2, 4}, PageInfo{Page: 1, TotalPages: 1, Total: 2, Limit: 2, FirstItem: 1, LastItem: 2}},
+		{"like", "filter[name][like]=*e*", []int{4}, PageInfo{Page: 1, TotalPages: 1, Total: 1, Limit: 1, FirstItem: 1, LastItem: 1}},
+		{"null", "filter[email][null]=true", []int{2, 3, 4}, PageInfo{Page: 1, TotalPages: 1, Total: 3, Limit: 3, FirstItem: 1, LastItem: 3}},
+		{"not null", "filter[email][null]=false", []int{1}, PageInfo{Page: 1, TotalPages: 1, Total: 1, Limit: 1, FirstItem: 1, LastItem: 1}},
+		{"nested field", "filter[address.city]=Seattle", []int{1, 3}, PageInfo{Page: 1, TotalPages: 1, Total: 2, Limit: 2, FirstItem: 1, LastItem: 2}},
+		{"sort descending with tie", "sort=-age,name", []int{4, 1, 3, 2}, PageInfo{Page: 1, TotalPages: 1, Total: 4, Limit: 4, FirstItem: 1, LastItem: 4}},
+		{"sort ascending prefix", "sort=%2Bjoined", []int{3, 1, 2, 4}, PageInfo{Page: 1, TotalPages: 1, Total: 4, Limit: 4, FirstItem: 1, LastItem: 4}},
+		{
+			"offset pagination",
+			"sort=id&page[limit]=3&page[offset]=3",
+			[]int{4},
+			PageInfo{Page: 2, TotalPages: 2, Total: 4, Limit: 3, Offset: 3, FirstItem: 4, LastItem: 4, HasPrev: true},
+		},
+		{
+			"page number pagination",
+			"filter[active]=true&sort=-id&page[number]=1&page[size]=2",
+			[]int{4, 3},
+			PageInfo{Page: 1, TotalPages: 2, Total: 3, Limit: 2, FirstItem: 1, LastItem: 2, HasNext: true},
+		},
+		{
+			"beyond the last page",
+			"page[limit]=2&page[offset]=10",
+			[]int{},
+			PageInfo{Page: 6, TotalPages: 2, Total: 4, Limit: 2, Offset: 10, HasPrev: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, pi, err := Apply(memoryPeople(), o)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := []int{}
+			for _, p := range got {
+				ids = append(ids, p.ID)
+			}
+
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("Apply() ids = %v, want %v", ids, tt.wantIDs)
+			}
+
+			if !reflect.DeepEqual(pi, tt.wantInfo) {
+				t.Errorf("Apply() page info = %+v, want %+v", pi, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestApply_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		qs       string
+		wantCode ErrorCode
+	}{
+		{"unknown filter field", "filter[secret]=x", ErrCodeNotFilterable},
+		{"unknown sort field", "sort=-secret", ErrCodeNotSortable},
+		{"unknown field", "fields=name,secret", ErrCodeNotSelectable},
+		{"invalid integer", "filter[age][gt]=old", ErrCodeInvalidValue},
+		{"invalid time", "filter[joined][gt]=yesterday", ErrCodeInvalidValue},
+		{"cursor pagination", "page[size]=3&page[after]=abc", ErrCodeInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, err = Apply(memoryPeople(), o)
+
+			var pe *ParseError
+			if !errors.As(err, &pe) || pe.Code != tt.wantCode {
+				t.Errorf("Apply() error = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestApply_fields(t *testing.T) {
+	o, err := FromQuerystring("fields=id,name&filter[id]=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	people := memoryPeople()
+	got, _, err := Apply(people, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []memoryPerson{{ID: 1, Name: "Ann"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %+v, want %+v", got, want)
+	}
+
+	if people[0].Age != 34 {
+		t.Error("Apply() modified the provided items")
+	}
+}
+
+type memoryBase struct {
+	ID int `json:"id"`
+}
+
+type memoryNamed struct {
+	Name string
+}
+
+type memoryLabeled struct {
+	Name string
+}
+
+type memoryRecord struct {
+	memoryBase
+
+	Label string `json:"label"`
+}
+
+type memoryAmbiguous struct {
+	memoryNamed
+	memoryLabeled
+}
+
+func TestApply_embedded(t *testing.T) {
+	items := []memoryRecord{
+		{memoryBase{1}, "a"},
+		{memoryBase{2}, "b"},
+		{memoryBase{3}, "c"},
+	}
+
+	o, err := FromQuerystring("filter[id][gte]=2&sort=-id&fields=id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := Apply(items, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []memoryRecord{{memoryBase: memoryBase{3}}, {memoryBase: memoryBase{2}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %+v, want %+v", got, want)
+	}
+
+	// fields of the same name and depth are not promoted
+	o, err = FromQuerystring("filter[Name]=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = Apply([]memoryAmbiguous{{}}, o)
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Code != ErrCodeNotFilterable {
+		t.Errorf("Apply() error = %v, want %v", err, ErrCodeNotFilterable)
+	}
+}
+
+func TestApply_nilItems(t *testing.T) {
+	o, err := FromQuerystring("fields=a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := Apply([]any{nil, map[string]any{"a": 1, "b": 2}}, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []any{nil, map[string]any{"a": 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+}
+
+func TestApply_maps(t *testing.T) {
+	items := []map[string]any{
+		{"id": 1.0, "name": "Ann", "tags": map[string]any{"team": "red"}},
+		{"id": 2.0, "name": "Bob", "tags": map[string]any{"team": "blue"}},
+		{"id": 3.0, "name": "Cy"},
+	}
+
+	o, err := FromQuerystring("filter[id][gte]=2&fields=name&sort=-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := Apply(items, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []map[string]any{{"name": "Cy"}, {"name": "Bob"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+
+	o, err = FromQuerystring("filter[tags.team]=red,green")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err = Apply(items, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || got[0]["name"] != "Ann" {
+		t.Errorf("Apply() = %v, want Ann", got)
+	}
+}
+
+func Test_matchWildcard(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"Ann", "Ann", true},
+		{"Ann", "Anne", false},
+		{"An*", "Anne", true},
+		{"*ne", "Anne", true},
+		{"*n*e", "Anne", true},
+		{"A*x*", "Anne", false},
+		{"*", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.value, func(t *testing.T) {
+			if got := matchWildcard(tt.pattern, tt.value); got != tt.want {
+				t.Errorf("matchWildcard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
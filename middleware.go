@@ -0,0 +1,110 @@
+package options
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// JSONAPIMediaType is the media type of JSON:API documents
+const JSONAPIMediaType = "application/vnd.api+json"
+
+type contextKey struct{}
+
+// MiddlewareConfig configures the Options parsed by Middleware
+type MiddlewareConfig struct {
+	// Config supplies the defaults and limits applied to each request
+	Config
+
+	// Range enables pagination via the Range request header (i.e.
+	// Range: items=0-24) when the request does not provide page values
+	Range bool
+
+	// RangeUnit is the unit accepted in the Range header, when empty
+	// RangeUnit (items) is accepted, a header with any other unit is
+	// ignored
+	RangeUnit string
+
+	// Schema, when provided, is used to validate the parsed Options
+	Schema *Schema
+}
+
+// Middleware returns net/http middleware that parses the Options of each
+// request, applying the configured defaults and limits, and stores them
+// in the request context for retrieval via FromContext
+//
+// When the querystring is invalid, a JSON:API error document is written
+// with a 400 status and the next handler is not called.
+func Middleware(cfg MiddlewareConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			o, err := cfg.parse(r)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), o)))
+		})
+	}
+}
+
+// NewContext returns a copy of the provided context containing the Options
+func NewContext(ctx context.Context, o Options) context.Context {
+	return context.WithValue(ctx, contextKey{}, o)
+}
+
+// FromContext returns the Options stored in the provided context by
+// Middleware, along with whether or not they were found
+func FromContext(ctx context.Context) (Options, bool) {
+	o, ok := ctx.Value(contextKey{}).(Options)
+	return o, ok
+}
+
+// WriteError writes the JSON:API error document for the provided error
+// with a 400 status
+func WriteError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", JSONAPIMediaType)
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(JSONAPIErrors(err))
+}
+
+func (cfg MiddlewareConfig) parse(r *http.Request) (Options, error) {
+	o, err := fromQuerystring(r.URL.RawQuery, &cfg.Config)
+	if err != nil {
+		return o, err
+	}
+
+	// a Range header is only used when no page values are provided
+	rh := r.Header.Get("Range")
+	if cfg.Range && rh != "" && len(o.Page) == 0 && len(o.Cursor) == 0 {
+		var units []string
+		if cfg.RangeUnit != "" {
+			units = append(units, cfg.RangeUnit)
+		}
+
+		err := o.SetRange(rh, units...)
+		if err != nil && !errors.Is(err, ErrUnsupportedRangeUnit) {
+			return o, ParseErrors{{
+				Code:   ErrCodeInvalidValue,
+				Header: "Range",
+				Value:  rh,
+				Detail: err.Error(),
+				Err:    err,
+			}}
+		}
+	}
+
+	if err := cfg.Apply(&o); err != nil {
+		return o, err
+	}
+
+	if cfg.Schema != nil {
+		if err := cfg.Schema.Validate(o); err != nil {
+			return o, err
+		}
+	}
+
+	return o, nil
+}
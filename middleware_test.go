@@ -0,0 +1,149 @@
+package options
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	cfg := MiddlewareConfig{
+		Config: Config{
+			DefaultPage: map[string]int{"limit": 25, "offset": 0},
+			MaxPageSize: 100,
+		},
+		Range: true,
+		Schema: &Schema{Fields: map[string]FieldSchema{
+			"name": {Filterable: true, Sortable: true},
+		}},
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		header     map[string]string
+		wantStatus int
+		wantPage   map[string]int
+		wantBody   string
+	}{
+		{
+			"defaults applied",
+			"/people?sort=name",
+			nil,
+			http.StatusOK,
+			map[string]int{"limit": 25, "offset": 0},
+			"page[limit]=25&page[offset]=25&sort=name",
+		},
+		{
+			"page provided",
+			"/people?page[size]=10&page[page]=2",
+			nil,
+			http.StatusOK,
+			map[string]int{"size": 10, "page": 2},
+			"page[size]=10&page[page]=3",
+		},
+		{
+			"range header",
+			"/people",
+			map[string]string{"Range": "items=50-74"},
+			http.StatusOK,
+			map[string]int{"limit": 25, "offset": 50},
+			"page[limit]=25&page[offset]=75",
+		},
+		{
+			"range header with another unit",
+			"/people",
+			map[string]string{"Range": "bytes=0-1023"},
+			http.StatusOK,
+			map[string]int{"limit": 25, "offset": 0},
+			"page[limit]=25&page[offset]=25",
+		},
+		{
+			"invalid range header",
+			"/people",
+			map[string]string{"Range": "items=50-"},
+			http.StatusBadRequest,
+			nil,
+			`"source":{"header":"Range"}`,
+		},
+		{
+			"limit exceeded",
+			"/people?page[limit]=1000000",
+			nil,
+			http.StatusBadRequest,
+			nil,
+			`"code":"max_size_exceeded","title":"Invalid Query Parameter","detail":"page size may not exceed 100","source":{"parameter":"page[limit]"}`,
+		},
+		{
+			"invalid value",
+			"/people?page[limit]=ten",
+			nil,
+			http.StatusBadRequest,
+			nil,
+			`"code":"invalid_value"`,
+		},
+		{
+			"schema violation",
+			"/people?filter[secret]=x",
+			nil,
+			http.StatusBadRequest,
+			nil,
+			`"code":"not_filterable"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *Options
+			h := Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				o, ok := FromContext(r.Context())
+				if !ok {
+					t.Fatal("FromContext() did not find Options")
+				}
+
+				got = &o
+				w.Write([]byte(o.Next()))
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Middleware() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("Middleware() body = %v, want %v", w.Body.String(), tt.wantBody)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				if got != nil {
+					t.Error("Middleware() called the next handler")
+				}
+
+				if ct := w.Header().Get("Content-Type"); ct != JSONAPIMediaType {
+					t.Errorf("Middleware() content type = %v, want %v", ct, JSONAPIMediaType)
+				}
+
+				return
+			}
+
+			if !reflect.DeepEqual(got.Page, tt.wantPage) {
+				t.Errorf("Middleware() page = %v, want %v", got.Page, tt.wantPage)
+			}
+		})
+	}
+}
+
+func TestFromContext_missing(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, ok := FromContext(r.Context()); ok {
+		t.Error("FromContext() found Options in an empty context")
+	}
+}
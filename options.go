@@ -1,33 +1,145 @@
 package options
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
 )
 
 // Options contain filtering, pagination and sorting instructions provided via
 // the querystring in bracketed object notation
+//
+// Filters contains a typed representation of each provided filter, including
+// any operator (i.e. filter[age][gte]=21), while Filter retains the values
+// keyed by field name with comparisons expressed as value prefixes (i.e.
+// >=21). Conditions that have no such form (i.e. between, like and null)
+// are omitted from Filter. When the values of a field in Filter are changed
+// after parsing, they replace the conditions in Filters for that field.
+//
+// FieldSets contains the sparse fieldsets provided per resource type (i.e.
+// fields[articles]=title,body), while Fields contains those provided without
+// a type (i.e. fields=title,body).
+//
+// Expression contains the boolean filter expression provided via the filter
+// parameter (i.e. filter=status eq 'open' or priority gt 3), which must be
+// satisfied in addition to any bracketed filters.
+//
+// Include contains the tree of relationship paths provided via the include
+// parameter (i.e. include=author,comments.author).
+//
+// Extra contains any other parameter provided in the querystring, which
+// is retained in generated querystrings unless omitted via OmitExtra.
+//
+// Cursor contains the opaque page[after] and page[before] values used for
+// cursor pagination, while Page contains all other (integer) page values.
 type Options struct {
-	ps IPaginationStrategy
-	qs string
-
-	Fields []string            `json:"fields,omitempty"`
-	Filter map[string][]string `json:"filter,omitempty"`
-	Page   map[string]int      `json:"page"`
-	Sort   []string            `json:"sort,omitempty"`
+	omit []string
+	ps   IPaginationStrategy
+
+	// Extra parameters provided without a value (i.e. ?flag)
+	flags map[string]bool
+
+	// the Filter values last derived from Filters
+	synced map[string][]string
+
+	Cursor     map[string]string   `json:"cursor,omitempty"`
+	Expression Expr                `json:"expression,omitempty"`
+	Extra      url.Values          `json:"extra,omitempty"`
+	Fields     []string            `json:"fields,omitempty"`
+	FieldSets  map[string][]string `json:"fieldsets,omitempty"`
+	Filter     map[string][]string `json:"filter,omitempty"`
+	Filters    []FilterCondition   `json:"filters,omitempty"`
+	Include    []Relationship      `json:"include,omitempty"`
+	Page       map[string]int      `json:"page"`
+	Sort       []string            `json:"sort,omitempty"`
 }
 
 // ContainsFilterField confirms whether or not the provided filter
 // parameters include the requested field
 func (o Options) ContainsFilterField(field string) bool {
 	fields := []string{}
-	for f := range o.Filter {
-		fields = append(fields, f)
+	for _, fc := range o.Conditions() {
+		fields = append(fields, fc.Field)
 	}
 
 	return contains(fields, field, false)
 }
 
+// Conditions returns the typed filter conditions of Filters, with those of
+// any field whose values in Filter have changed since they were derived
+// from Filters replaced by conditions read from the Filter values
+func (o Options) Conditions() []FilterCondition {
+	fields := o.changedFilterFields()
+	if len(fields) == 0 {
+		return o.Filters
+	}
+
+	changed := map[string]bool{}
+	for _, field := range fields {
+		changed[field] = true
+	}
+
+	fcs := make([]FilterCondition, 0, len(o.Filters)+len(fields))
+	for _, fc := range o.Filters {
+		// conditions that Filter can not express are retained
+		if _, ok := fc.legacyValues(); ok && changed[fc.Field] {
+			continue
+		}
+
+		fcs = append(fcs, fc)
+	}
+
+	for _, field := range fields {
+		fcs = append(fcs, legacyConditions(field, o.Filter[field])...)
+	}
+
+	return fcs
+}
+
+// changedFilterFields returns the fields (in order) with values in Filter
+// that differ from those last derived from Filters
+func (o Options) changedFilterFields() []string {
+	var fields []string
+
+	for field, values := range o.Filter {
+		if !equalValues(values, o.synced[field]) {
+			fields = append(fields, field)
+		}
+	}
+
+	for field := range o.synced {
+		if _, ok := o.Filter[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
+
+// syncFilter derives Filter from the conditions in Filters
+func (o *Options) syncFilter() {
+	o.Filter = map[string][]string{}
+	o.synced = nil
+
+	for _, fc := range o.Filters {
+		values, ok := fc.legacyValues()
+		if !ok {
+			continue
+		}
+
+		if o.synced == nil {
+			o.synced = map[string][]string{}
+		}
+
+		o.Filter[fc.Field] = append(o.Filter[fc.Field], values...)
+		o.synced[fc.Field] = append(o.synced[fc.Field], values...)
+	}
+}
+
 // ContainsSortField confirms whether or not the provided sort options
 // contains the requested field
 func (o Options) ContainsSortField(field string) bool {
@@ -37,12 +149,12 @@ func (o Options) ContainsSortField(field string) bool {
 // First returns a querystring for the first page
 func (o Options) First() string {
 	if len(o.Page) == 0 || o.ps == nil {
-		return buildQuerystring(o.Filter, o.Fields, "", o.Sort)
+		return o.querystring("")
 	}
 
 	// determine next page numbers based on pagination strategy
 	po := o.ps.First(o.Page)
-	qs := buildQuerystring(o.Filter, o.Fields, po, o.Sort)
+	qs := o.querystring(po)
 
 	return qs
 }
@@ -50,12 +162,12 @@ func (o Options) First() string {
 // Last returns a querystring for the last page
 func (o Options) Last(total int) string {
 	if len(o.Page) == 0 || o.ps == nil {
-		return buildQuerystring(o.Filter, o.Fields, "", o.Sort)
+		return o.querystring("")
 	}
 
 	// determine next page numbers based on pagination strategy
 	po := o.ps.Last(o.Page, total)
-	qs := buildQuerystring(o.Filter, o.Fields, po, o.Sort)
+	qs := o.querystring(po)
 
 	return qs
 }
@@ -63,16 +175,80 @@ func (o Options) Last(total int) string {
 // Next returns a querystring for the next page
 func (o Options) Next() string {
 	if len(o.Page) == 0 || o.ps == nil {
-		return buildQuerystring(o.Filter, o.Fields, "", o.Sort)
+		return o.querystring("")
 	}
 
 	// determine next page numbers based on pagination strategy
 	po := o.ps.Next(o.Page)
-	qs := buildQuerystring(o.Filter, o.Fields, po, o.Sort)
+	qs := o.querystring(po)
 
 	return qs
 }
 
+// LimitOffset returns the limit and offset of the current page when
+// the pagination strategy implements ILimitOffsetStrategy
+func (o Options) LimitOffset() (int, int, bool) {
+	los, ok := o.ps.(ILimitOffsetStrategy)
+	if !ok || len(o.Page) == 0 {
+		return 0, 0, false
+	}
+
+	return los.LimitOffset(o.Page)
+}
+
+// MarshalJSON encodes the Options with the Expression in the form accepted
+// by the filter parameter, as the nodes of an Expr can not be decoded
+func (o Options) MarshalJSON() ([]byte, error) {
+	type options Options
+
+	var expr string
+	if o.Expression != nil {
+		expr = o.Expression.String()
+	}
+
+	return json.Marshal(struct {
+		options
+		Expression string `json:"expression,omitempty"`
+	}{options(o), expr})
+}
+
+// UnmarshalJSON decodes Options encoded by MarshalJSON, parsing the
+// Expression
+func (o *Options) UnmarshalJSON(b []byte) error {
+	type options Options
+
+	v := struct {
+		*options
+		Expression string `json:"expression,omitempty"`
+	}{options: (*options)(o)}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	o.Expression = nil
+	if v.Expression != "" {
+		e, err := ParseExpression(v.Expression)
+		if err != nil {
+			return err
+		}
+
+		o.Expression = e
+	}
+
+	// Filter is derived from Filters, as it is when parsing
+	if len(o.Filters) > 0 {
+		o.syncFilter()
+	}
+
+	return nil
+}
+
+// OmitExtra specifies parameters from Extra that should not be retained
+// in the querystrings generated by String, First, Last, Next and Prev
+func (o *Options) OmitExtra(params ...string) {
+	o.omit = append(o.omit, params...)
+}
+
 // PaginationStrategy can be used to retrieve the current
 // IPaginationStrategy that the Options struct will use for
 // generating Prev, Next, First and Last querystring values
@@ -83,12 +259,12 @@ func (o Options) PaginationStrategy() IPaginationStrategy {
 // Prev returns a querystring for the previous page
 func (o Options) Prev() string {
 	if len(o.Page) == 0 || o.ps == nil {
-		return buildQuerystring(o.Filter, o.Fields, "", o.Sort)
+		return o.querystring("")
 	}
 
 	// determine previous page numbers based on pagination strategy
 	po := o.ps.Prev(o.Page)
-	qs := buildQuerystring(o.Filter, o.Fields, po, o.Sort)
+	qs := o.querystring(po)
 
 	return qs
 }
@@ -96,10 +272,10 @@ func (o Options) Prev() string {
 // String returns a querystring for the current page
 func (o Options) String() string {
 	if o.Page == nil || o.ps == nil {
-		return buildQuerystring(o.Filter, o.Fields, "", o.Sort)
+		return o.querystring("")
 	}
 
-	return buildQuerystring(o.Filter, o.Fields, o.ps.Current(o.Page), o.Sort)
+	return o.querystring(o.ps.Current(o.Page))
 }
 
 // SetPaginationStrategy can be used to specify custom pagination
@@ -108,12 +284,12 @@ func (o *Options) SetPaginationStrategy(ps IPaginationStrategy) {
 	o.ps = ps
 }
 
-func buildQuerystring(filter map[string][]string, fields []string, page string, sort []string) string {
+func (o Options) querystring(page string) string {
 	b := strings.Builder{}
 	ra := false
 
 	// filters
-	for field, filter := range filter {
+	for _, fc := range o.Conditions() {
 		if ra {
 			fmt.Fprint(&b, "&")
 		}
@@ -121,31 +297,61 @@ func buildQuerystring(filter map[string][]string, fields []string, page string,
 		// & is required on subsequent iterations
 		ra = true
 
-		// write the filter for the field to the builder
-		fmt.Fprintf(&b, "filter[%s]=", field)
-		for i, value := range filter {
-			// add a comma if multiple values are specified
-			if i > 0 {
-				fmt.Fprint(&b, ",")
-			}
-			fmt.Fprint(&b, value)
+		fmt.Fprint(&b, fc.String())
+	}
+
+	// filter expression
+	if o.Expression != nil {
+		if ra {
+			fmt.Fprint(&b, "&")
 		}
+
+		// & is required on subsequent iterations
+		ra = true
+
+		fmt.Fprintf(&b, "filter=%s", url.QueryEscape(o.Expression.String()))
 	}
 
 	// field projections
-	if len(fields) > 0 {
+	if len(o.Fields) > 0 {
 		if ra {
 			fmt.Fprint(&b, "&")
 		}
-		fmt.Fprintf(&b, "fields=")
-		for i, field := range fields {
-			// add a comma if multiple fields are specified
-			if i > 0 {
-				fmt.Fprint(&b, ",")
-			}
 
-			fmt.Fprint(&b, field)
+		// & is required on subsequent iterations
+		ra = true
+
+		fmt.Fprintf(&b, "fields=%s", joinNames(o.Fields))
+	}
+
+	// typed field projections
+	types := make([]string, 0, len(o.FieldSets))
+	for typ := range o.FieldSets {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+
+	for _, typ := range types {
+		if ra {
+			fmt.Fprint(&b, "&")
+		}
+
+		// & is required on subsequent iterations
+		ra = true
+
+		fmt.Fprintf(&b, "fields[%s]=%s", escapeKey(typ), joinNames(o.FieldSets[typ]))
+	}
+
+	// relationships
+	if paths := o.IncludePaths(); len(paths) > 0 {
+		if ra {
+			fmt.Fprint(&b, "&")
 		}
+
+		// & is required on subsequent iterations
+		ra = true
+
+		fmt.Fprintf(&b, "include=%s", joinNames(paths))
 	}
 
 	// pagination
@@ -161,24 +367,62 @@ func buildQuerystring(filter map[string][]string, fields []string, page string,
 	}
 
 	// sorting
-	if len(sort) > 0 {
+	if len(o.Sort) > 0 {
 		if ra {
 			fmt.Fprint(&b, "&")
 		}
-		fmt.Fprintf(&b, "sort=")
-		for i, field := range sort {
-			// add a comma if multiple fields are specified
-			if i > 0 {
-				fmt.Fprint(&b, ",")
+
+		// & is required on subsequent iterations
+		ra = true
+
+		fmt.Fprintf(&b, "sort=%s", joinNames(o.Sort))
+	}
+
+	// other parameters (in key order)
+	keys := make([]string, 0, len(o.Extra))
+	for key := range o.Extra {
+		if !contains(o.omit, key, false) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range o.Extra[key] {
+			if ra {
+				fmt.Fprint(&b, "&")
+			}
+
+			// & is required on subsequent iterations
+			ra = true
+
+			// parameters provided without a value are written without one
+			if value == "" && o.flags[key] {
+				fmt.Fprint(&b, url.QueryEscape(key))
+				continue
 			}
 
-			fmt.Fprint(&b, field)
+			fmt.Fprintf(&b, "%s=%s", url.QueryEscape(key), url.QueryEscape(value))
 		}
 	}
 
 	return b.String()
 }
 
+func equalValues(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
 func contains(list []string, value string, stripPrefix bool) bool {
 	if len(list) == 0 {
 		return false
@@ -191,13 +435,7 @@ func contains(list []string, value string, stripPrefix bool) bool {
 	for _, search := range list {
 		// check to see if prefix should be stripped
 		if stripPrefix {
-			if len(search) > 2 && (search[0:2] == "<=" || search[0:2] == ">=" || search[0:2] == "!=") {
-				search = search[2:]
-			}
-
-			if len(search) > 1 && (search[0:1] == "<" || search[0:1] == ">" || search[0:1] == "-" || search[0:1] == "+" || search[0:1] == "!") {
-				search = search[1:]
-			}
+			search = trimPrefix(search)
 		}
 
 		if search == value {
@@ -207,3 +445,17 @@ func contains(list []string, value string, stripPrefix bool) bool {
 
 	return false
 }
+
+// trimPrefix removes any comparison or direction prefix (i.e. <=, -)
+// from the provided value
+func trimPrefix(value string) string {
+	if len(value) > 2 && (value[0:2] == "<=" || value[0:2] == ">=" || value[0:2] == "!=") {
+		value = value[2:]
+	}
+
+	if len(value) > 1 && (value[0:1] == "<" || value[0:1] == ">" || value[0:1] == "-" || value[0:1] == "+" || value[0:1] == "!") {
+		value = value[1:]
+	}
+
+	return value
+}
@@ -1,6 +1,8 @@
 package options
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -99,7 +101,6 @@ func TestOptions_First(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			o := Options{
 				ps:     tt.fields.ps,
-				qs:     tt.fields.qs,
 				Filter: tt.fields.Filter,
 				Page:   tt.fields.Page,
 				Sort:   tt.fields.Sort,
@@ -206,7 +207,6 @@ func TestOptions_Next(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			o := Options{
 				ps:     tt.fields.ps,
-				qs:     tt.fields.qs,
 				Filter: tt.fields.Filter,
 				Page:   tt.fields.Page,
 				Sort:   tt.fields.Sort,
@@ -313,7 +313,6 @@ func TestOptions_Prev(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			o := Options{
 				ps:     tt.fields.ps,
-				qs:     tt.fields.qs,
 				Filter: tt.fields.Filter,
 				Page:   tt.fields.Page,
 				Sort:   tt.fields.Sort,
@@ -369,6 +368,24 @@ func TestOptions_Last(t *testing.T) {
 		},
 			args{total: 150},
 			"page[limit]=100&page[offset]=100"},
+		{"total is a multiple of limit, offset strategy", fields{
+			OffsetStrategy{},
+			"page[limit]=100&page[offset]=0",
+			map[string][]string{},
+			map[string]int{"offset": 0, "limit": 100},
+			[]string{},
+		},
+			args{total: 300},
+			"page[limit]=100&page[offset]=200"},
+		{"no results, offset strategy", fields{
+			OffsetStrategy{},
+			"page[limit]=100&page[offset]=0",
+			map[string][]string{},
+			map[string]int{"offset": 0, "limit": 100},
+			[]string{},
+		},
+			args{total: 0},
+			"page[limit]=100&page[offset]=0"},
 		{"no filters, no sorting, offset not zero, offset strategy", fields{
 			OffsetStrategy{},
 			"page[limit]=100&page[offset]=200",
@@ -422,7 +439,7 @@ func TestOptions_Last(t *testing.T) {
 			[]string{},
 		},
 			args{total: 1000},
-			"page[size]=100&page[page]=10"},
+			"page[size]=100&page[page]=9"},
 		{"with filters, no sorting, pagesize strategy", fields{
 			PageSizeStrategy{},
 			"filter[fieldA]=valueA,valueB&page[size]=100&page[page]=1000",
@@ -446,7 +463,6 @@ func TestOptions_Last(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			o := Options{
 				ps:     tt.fields.ps,
-				qs:     tt.fields.qs,
 				Filter: tt.fields.Filter,
 				Page:   tt.fields.Page,
 				Sort:   tt.fields.Sort,
@@ -553,7 +569,6 @@ func TestOptions_String(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			o := Options{
 				ps:     tt.fields.ps,
-				qs:     tt.fields.qs,
 				Filter: tt.fields.Filter,
 				Page:   tt.fields.Page,
 				Sort:   tt.fields.Sort,
@@ -630,3 +645,247 @@ func Test_contains(t *testing.T) {
 		})
 	}
 }
+
+func TestOptions_String_fieldSets(t *testing.T) {
+	o := Options{
+		Fields:    []string{"id"},
+		FieldSets: map[string][]string{"people": {"name"}, "articles": {"title", "body"}},
+		Sort:      []string{"-created"},
+	}
+
+	want := "fields=id&fields[articles]=title,body&fields[people]=name&sort=-created"
+	if got := o.String(); got != want {
+		t.Errorf("Options.String() = %v, want %v", got, want)
+	}
+
+	rt, err := FromQuerystring(o.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(rt.FieldSets, o.FieldSets) {
+		t.Errorf("FromQuerystring() fieldsets = %v, want %v", rt.FieldSets, o.FieldSets)
+	}
+}
+
+func TestOptions_Next_extra(t *testing.T) {
+	o, err := FromQuerystring("q=search%20term&page[limit]=10&page[offset]=0&api_version=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "page[limit]=10&page[offset]=10&api_version=2&q=search+term"
+	if got := o.Next(); got != want {
+		t.Errorf("Options.Next() = %v, want %v", got, want)
+	}
+
+	o.OmitExtra("api_version")
+
+	want = "page[limit]=10&page[offset]=10&q=search+term"
+	if got := o.Next(); got != want {
+		t.Errorf("Options.Next() = %v, want %v", got, want)
+	}
+}
+
+func TestOptions_Next_extraWithoutValue(t *testing.T) {
+	o, err := FromQuerystring("pretty&page[limit]=10&page[offset]=0&empty=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "page[limit]=10&page[offset]=10&empty=&pretty"
+	if got := o.Next(); got != want {
+		t.Errorf("Options.Next() = %v, want %v", got, want)
+	}
+}
+
+func TestOptions_pageNumberStrategy(t *testing.T) {
+	tests := []struct {
+		name      string
+		ps        PageNumberStrategy
+		page      map[string]int
+		wantFirst string
+		wantLast  string
+		wantNext  string
+		wantPrev  string
+		wantStr   string
+	}{
+		{
+			"1-based, first page",
+			PageNumberStrategy{Base: 1},
+			map[string]int{"number": 1, "size": 10},
+			"page[number]=1&page[size]=10",
+			"page[number]=10&page[size]=10",
+			"page[number]=2&page[size]=10",
+			"page[number]=1&page[size]=10",
+			"page[number]=1&page[size]=10",
+		},
+		{
+			"1-based, middle page",
+			PageNumberStrategy{Base: 1},
+			map[string]int{"number": 4, "size": 10},
+			"page[number]=1&page[size]=10",
+			"page[number]=10&page[size]=10",
+			"page[number]=5&page[size]=10",
+			"page[number]=3&page[size]=10",
+			"page[number]=4&page[size]=10",
+		},
+		{
+			"1-based, missing number",
+			PageNumberStrategy{Base: 1},
+			map[string]int{"size": 10},
+			"page[number]=1&page[size]=10",
+			"page[number]=10&page[size]=10",
+			"page[number]=2&page[size]=10",
+			"page[number]=1&page[size]=10",
+			"page[number]=1&page[size]=10",
+		},
+		{
+			"0-based, first page",
+			PageNumberStrategy{},
+			map[string]int{"number": 0, "size": 10},
+			"page[number]=0&page[size]=10",
+			"page[number]=9&page[size]=10",
+			"page[number]=1&page[size]=10",
+			"page[number]=0&page[size]=10",
+			"page[number]=0&page[size]=10",
+		},
+		{
+			"missing size",
+			PageNumberStrategy{Base: 1},
+			map[string]int{"number": 2},
+			"",
+			"",
+			"",
+			"",
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := Options{ps: tt.ps, Page: tt.page}
+
+			if got := o.First(); got != tt.wantFirst {
+				t.Errorf("Options.First() = %v, want %v", got, tt.wantFirst)
+			}
+
+			if got := o.Last(100); got != tt.wantLast {
+				t.Errorf("Options.Last() = %v, want %v", got, tt.wantLast)
+			}
+
+			if got := o.Next(); got != tt.wantNext {
+				t.Errorf("Options.Next() = %v, want %v", got, tt.wantNext)
+			}
+
+			if got := o.Prev(); got != tt.wantPrev {
+				t.Errorf("Options.Prev() = %v, want %v", got, tt.wantPrev)
+			}
+
+			if got := o.String(); got != tt.wantStr {
+				t.Errorf("Options.String() = %v, want %v", got, tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestFromQuerystring_pageNumber(t *testing.T) {
+	o, err := FromQuerystring("page[number]=2&page[size]=100")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps, ok := o.PaginationStrategy().(*PageNumberStrategy)
+	if !ok || ps.Base != 1 {
+		t.Fatalf("FromQuerystring() strategy = %#v, want 1-based *PageNumberStrategy", o.PaginationStrategy())
+	}
+
+	if l, off, ok := o.LimitOffset(); !ok || l != 100 || off != 100 {
+		t.Errorf("Options.LimitOffset() = %d, %d, %v, want 100, 100, true", l, off, ok)
+	}
+
+	if got := o.Next(); got != "page[number]=3&page[size]=100" {
+		t.Errorf("Options.Next() = %v, want page[number]=3&page[size]=100", got)
+	}
+}
+
+func TestOptions_Conditions(t *testing.T) {
+	qs := "filter[status]=open&filter[age][gte]=21&filter[created][between]=2020-01-01,2020-12-31"
+
+	tests := []struct {
+		name string
+		edit func(o *Options)
+		want string
+	}{
+		{
+			"unchanged",
+			func(o *Options) {},
+			"filter[status]=open&filter[age][gte]=21&filter[created][between]=2020-01-01,2020-12-31",
+		},
+		{
+			"filter values changed",
+			func(o *Options) { o.Filter["status"] = []string{"closed", "<=3"} },
+			"filter[age][gte]=21&filter[created][between]=2020-01-01,2020-12-31&filter[status][lte]=3&filter[status]=closed",
+		},
+		{
+			"filter field removed",
+			func(o *Options) { delete(o.Filter, "age") },
+			"filter[status]=open&filter[created][between]=2020-01-01,2020-12-31",
+		},
+		{
+			"filter field added",
+			func(o *Options) { o.Filter["owner"] = []string{"me"} },
+			"filter[status]=open&filter[age][gte]=21&filter[created][between]=2020-01-01,2020-12-31&filter[owner]=me",
+		},
+		{
+			"condition added",
+			func(o *Options) { o.Filters = append(o.Filters, FilterCondition{"owner", OpEq, []string{"me"}}) },
+			"filter[status]=open&filter[age][gte]=21&filter[created][between]=2020-01-01,2020-12-31&filter[owner]=me",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tt.edit(&o)
+
+			if got := o.String(); got != tt.want {
+				t.Errorf("Options.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions_JSON(t *testing.T) {
+	o, err := FromQuerystring("filter[age][gte]=21&filter[status]=open,closed" +
+		"&filter=(status eq 'open' or name like 'jo*') and not deleted null true" +
+		"&sort=-age&page[limit]=10&page[offset]=20")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Options
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Expression, o.Expression) {
+		t.Errorf("Options.Expression = %v, want %v", got.Expression, o.Expression)
+	}
+
+	got.SetPaginationStrategy(o.PaginationStrategy())
+	if got.String() != o.String() {
+		t.Errorf("Options.String() = %v, want %v", got.String(), o.String())
+	}
+
+	if err := json.Unmarshal([]byte(`{"expression":"status eq"}`), &got); err == nil {
+		t.Error("json.Unmarshal() expected an error for an invalid expression")
+	}
+}
@@ -0,0 +1,104 @@
+package options
+
+// PageInfo describes the current page relative to the total number
+// of items available
+type PageInfo struct {
+	// Page is the 1-based number of the current page
+	Page int `json:"page"`
+
+	// TotalPages is the number of pages required for all items
+	TotalPages int `json:"totalPages"`
+
+	// Total is the number of items available
+	Total int `json:"total"`
+
+	// Limit and Offset describe the items of the current page
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+
+	// FirstItem and LastItem are the 1-based positions of the first and
+	// last items of the current page, both are 0 when the page is empty
+	FirstItem int `json:"firstItem"`
+	LastItem  int `json:"lastItem"`
+
+	HasNext bool `json:"hasNext"`
+	HasPrev bool `json:"hasPrev"`
+}
+
+// PageInfo returns details of the current page for the provided total
+// number of items, when the pagination strategy does not implement
+// ILimitOffsetStrategy all items are considered to be on a single page
+func (o Options) PageInfo(total int) PageInfo {
+	if total < 0 {
+		total = 0
+	}
+
+	limit, offset, ok := o.LimitOffset()
+	if !ok {
+		limit = total
+		offset = 0
+	}
+
+	// a page without a positive limit contains no items
+	if limit < 0 {
+		limit = 0
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	pi := PageInfo{
+		Page:   1,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	if limit > 0 {
+		pi.Page = offset/limit + 1
+		pi.TotalPages = (total + limit - 1) / limit
+	}
+
+	if offset < total && limit > 0 {
+		pi.FirstItem = offset + 1
+		pi.LastItem = offset + limit
+		if pi.LastItem > total {
+			pi.LastItem = total
+		}
+	}
+
+	pi.HasNext = limit > 0 && offset+limit < total
+	pi.HasPrev = offset > 0 && total > 0
+
+	return pi
+}
+
+// NextPage returns a querystring for the next page along with true,
+// or an empty querystring and false when the current page is the last
+// page for the provided total
+func (o Options) NextPage(total int) (string, bool) {
+	if !o.PageInfo(total).HasNext {
+		return "", false
+	}
+
+	return o.Next(), true
+}
+
+// PrevPage returns a querystring for the previous page along with true,
+// or an empty querystring and false when the current page is the first
+// page, when the current page is beyond the provided total, the last
+// page is returned
+func (o Options) PrevPage(total int) (string, bool) {
+	pi := o.PageInfo(total)
+	if !pi.HasPrev {
+		return "", false
+	}
+
+	// the current page is beyond the available items
+	if pi.Offset >= total {
+		return o.Last(total), true
+	}
+
+	return o.Prev(), true
+}
@@ -0,0 +1,106 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptions_PageInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		qs    string
+		total int
+		want  PageInfo
+	}{
+		{
+			"first page, offset strategy",
+			"page[limit]=10&page[offset]=0",
+			95,
+			PageInfo{Page: 1, TotalPages: 10, Total: 95, Limit: 10, Offset: 0, FirstItem: 1, LastItem: 10, HasNext: true},
+		},
+		{
+			"last partial page, offset strategy",
+			"page[limit]=10&page[offset]=90",
+			95,
+			PageInfo{Page: 10, TotalPages: 10, Total: 95, Limit: 10, Offset: 90, FirstItem: 91, LastItem: 95, HasPrev: true},
+		},
+		{
+			"last page when total is a multiple of limit, pagesize strategy",
+			"page[size]=10&page[page]=9",
+			100,
+			PageInfo{Page: 10, TotalPages: 10, Total: 100, Limit: 10, Offset: 90, FirstItem: 91, LastItem: 100, HasPrev: true},
+		},
+		{
+			"beyond the last page",
+			"page[limit]=10&page[offset]=200",
+			95,
+			PageInfo{Page: 21, TotalPages: 10, Total: 95, Limit: 10, Offset: 200, HasPrev: true},
+		},
+		{
+			"no results",
+			"page[limit]=10&page[offset]=0",
+			0,
+			PageInfo{Page: 1, Limit: 10},
+		},
+		{
+			"no pagination",
+			"sort=name",
+			42,
+			PageInfo{Page: 1, TotalPages: 1, Total: 42, Limit: 42, FirstItem: 1, LastItem: 42},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := o.PageInfo(tt.total); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Options.PageInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions_PageInfo_zeroLimit(t *testing.T) {
+	o := Options{Page: map[string]int{"limit": 0, "offset": 0}}
+	o.SetPaginationStrategy(&OffsetStrategy{})
+
+	want := PageInfo{Page: 1, Total: 10}
+	if got := o.PageInfo(10); !reflect.DeepEqual(got, want) {
+		t.Errorf("Options.PageInfo() = %+v, want %+v", got, want)
+	}
+}
+
+func TestOptions_NextPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		qs       string
+		total    int
+		wantNext string
+		wantPrev string
+	}{
+		{"middle page", "page[limit]=10&page[offset]=10", 95, "page[limit]=10&page[offset]=20", "page[limit]=10&page[offset]=0"},
+		{"first page", "page[limit]=10&page[offset]=0", 95, "page[limit]=10&page[offset]=10", ""},
+		{"last page", "page[limit]=10&page[offset]=90", 100, "", "page[limit]=10&page[offset]=80"},
+		{"beyond the last page", "page[limit]=10&page[offset]=500", 100, "", "page[limit]=10&page[offset]=90"},
+		{"last page, pagesize strategy", "page[size]=10&page[page]=9", 100, "", "page[size]=10&page[page]=8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, ok := o.NextPage(tt.total); got != tt.wantNext || ok != (tt.wantNext != "") {
+				t.Errorf("Options.NextPage() = %v, %v, want %v", got, ok, tt.wantNext)
+			}
+
+			if got, ok := o.PrevPage(tt.total); got != tt.wantPrev || ok != (tt.wantPrev != "") {
+				t.Errorf("Options.PrevPage() = %v, %v, want %v", got, ok, tt.wantPrev)
+			}
+		})
+	}
+}
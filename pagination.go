@@ -13,6 +13,12 @@ type IPaginationStrategy interface {
 	Prev(map[string]int) string
 }
 
+// ILimitOffsetStrategy is an interface for pagination strategies
+// that are able to express the current page as a limit and offset
+type ILimitOffsetStrategy interface {
+	LimitOffset(map[string]int) (int, int, bool)
+}
+
 // OffsetStrategy is a pagination strategy for page[offset] and
 // page[limit] parameters
 type OffsetStrategy struct{}
@@ -42,6 +48,16 @@ func (os OffsetStrategy) Current(c map[string]int) string {
 	return fmt.Sprintf("page[limit]=%d&page[offset]=%d", l, o)
 }
 
+// LimitOffset returns the limit and offset for the current page
+func (os OffsetStrategy) LimitOffset(c map[string]int) (int, int, bool) {
+	l, ok := c["limit"]
+	if !ok {
+		return 0, 0, false
+	}
+
+	return l, c["offset"], true
+}
+
 // First returns a link to the first page
 func (os OffsetStrategy) First(c map[string]int) string {
 	var (
@@ -77,7 +93,16 @@ func (os OffsetStrategy) Last(c map[string]int, total int) string {
 		return ""
 	}
 
-	o = total / l * l
+	// avoid dividing by zero
+	if l <= 0 {
+		return ""
+	}
+
+	// the last page begins at the final multiple of limit that is
+	// less than total
+	if total > 0 {
+		o = (total - 1) / l * l
+	}
 
 	return fmt.Sprintf("page[limit]=%d&page[offset]=%d", l, o)
 }
@@ -164,6 +189,16 @@ func (ps PageSizeStrategy) Current(c map[string]int) string {
 	return fmt.Sprintf("page[size]=%d&page[page]=%d", s, p)
 }
 
+// LimitOffset returns the limit and offset for the current page
+func (ps PageSizeStrategy) LimitOffset(c map[string]int) (int, int, bool) {
+	s, ok := c["size"]
+	if !ok {
+		return 0, 0, false
+	}
+
+	return s, c["page"] * s, true
+}
+
 // First returns a link to the first page
 func (ps PageSizeStrategy) First(c map[string]int) string {
 	var (
@@ -199,7 +234,16 @@ func (os PageSizeStrategy) Last(c map[string]int, total int) string {
 		return ""
 	}
 
-	p = total / s
+	// avoid dividing by zero
+	if s <= 0 {
+		return ""
+	}
+
+	// pages are zero-based, so the last page is one less than the
+	// number of pages
+	if total > 0 {
+		p = (total - 1) / s
+	}
 
 	return fmt.Sprintf("page[size]=%d&page[page]=%d", s, p)
 }
@@ -256,3 +300,151 @@ func (ps PageSizeStrategy) Prev(c map[string]int) string {
 
 	return fmt.Sprintf("page[size]=%d&page[page]=%d", s, p)
 }
+
+// PageNumberStrategy is a pagination strategy for page[number] and
+// page[size] parameters, where Base is the number of the first page
+// (either 0 or 1)
+type PageNumberStrategy struct {
+	Base int
+}
+
+// Current returns a link to the current page
+func (ps PageNumberStrategy) Current(c map[string]int) string {
+	var (
+		n int
+		s int
+	)
+
+	// read size
+	if size, ok := c["size"]; ok {
+		s = size
+	} else {
+		// if size isn't provided, return whatever was passed in
+		return ""
+	}
+
+	// read number
+	if number, ok := c["number"]; ok {
+		n = number
+	} else {
+		n = ps.Base
+	}
+
+	return fmt.Sprintf("page[number]=%d&page[size]=%d", n, s)
+}
+
+// LimitOffset returns the limit and offset for the current page
+func (ps PageNumberStrategy) LimitOffset(c map[string]int) (int, int, bool) {
+	s, ok := c["size"]
+	if !ok {
+		return 0, 0, false
+	}
+
+	n, ok := c["number"]
+	if !ok || n < ps.Base {
+		n = ps.Base
+	}
+
+	return s, (n - ps.Base) * s, true
+}
+
+// First returns a link to the first page
+func (ps PageNumberStrategy) First(c map[string]int) string {
+	var (
+		n int
+		s int
+	)
+
+	// read size
+	if size, ok := c["size"]; ok {
+		s = size
+	} else {
+		// if size isn't provided, return whatever was passed in
+		return ""
+	}
+
+	n = ps.Base
+
+	return fmt.Sprintf("page[number]=%d&page[size]=%d", n, s)
+}
+
+// Last returns a link to the last page
+func (ps PageNumberStrategy) Last(c map[string]int, total int) string {
+	var (
+		n int
+		s int
+	)
+
+	// read size
+	if size, ok := c["size"]; ok {
+		s = size
+	} else {
+		// if size isn't provided, return whatever was passed in
+		return ""
+	}
+
+	// avoid dividing by zero
+	if s <= 0 {
+		return ""
+	}
+
+	n = ps.Base
+	if total > 0 {
+		n += (total - 1) / s
+	}
+
+	return fmt.Sprintf("page[number]=%d&page[size]=%d", n, s)
+}
+
+// Next returns a link to the next page
+func (ps PageNumberStrategy) Next(c map[string]int) string {
+	var (
+		n int
+		s int
+	)
+
+	// read size
+	if size, ok := c["size"]; ok {
+		s = size
+	} else {
+		// if size isn't provided, return whatever was passed in
+		return ""
+	}
+
+	if number, ok := c["number"]; ok {
+		n = number + 1
+	} else {
+		n = ps.Base + 1
+	}
+
+	return fmt.Sprintf("page[number]=%d&page[size]=%d", n, s)
+}
+
+// Prev returns a link to the previous page
+func (ps PageNumberStrategy) Prev(c map[string]int) string {
+	var (
+		n int
+		s int
+	)
+
+	// read size
+	if size, ok := c["size"]; ok {
+		s = size
+	} else {
+		// if size isn't provided, return whatever was passed in
+		return ""
+	}
+
+	if number, ok := c["number"]; ok {
+		n = number - 1
+	} else {
+		n = ps.Base
+	}
+
+	// don't allow the number to go below the first page
+	if n < ps.Base {
+		n = ps.Base
+	}
+
+	return fmt.Sprintf("page[number]=%d&page[size]=%d", n, s)
+}
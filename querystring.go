@@ -4,173 +4,339 @@
 package options
 
 import (
-	"errors"
 	"fmt"
 	"net/url"
-	"regexp"
 	"strconv"
 	"strings"
 )
 
-var (
-	bracketRE      = regexp.MustCompile(`(?P<typ>filter|sort|page)\[([^&]+?)\](\={1})`)
-	bracketValueRE = regexp.MustCompile(`\]\=(.*?)(\&|\z)`)
-	commaRE        = regexp.MustCompile(`\s?\,\s?`)
-	fieldsRE       = regexp.MustCompile(`fields=(?P<field>.+?)(\&|\z)`)
-	sortRE         = regexp.MustCompile(`sort=(?P<field>.+?)(\&|\z)`)
-)
+// DefaultMaxQuerystringSize is the length (in bytes) of the largest
+// querystring parsed when Config.MaxQuerystringSize is 0, bounding the
+// cost of parsing a single request
+const DefaultMaxQuerystringSize = 16 * 1024
 
 // FromQuerystring parses an Options object from the provided querystring
 func FromQuerystring(qs string) (Options, error) {
+	return fromQuerystring(qs, &Config{})
+}
+
+// fromQuerystring parses an Options object from the provided querystring
+// within the parsing limits of the provided Config
+func fromQuerystring(qs string, c *Config) (Options, error) {
 	if qs == "" {
 		return Options{}, nil
 	}
 
-	uqs, err := url.QueryUnescape(qs)
-	if err != nil {
-		return Options{}, err
+	if max := c.maxQuerystringSize(); max > 0 && len(qs) > max {
+		return Options{}, ParseErrors{{
+			Code:   ErrCodeLimitExceeded,
+			Value:  strconv.Itoa(len(qs)),
+			Detail: fmt.Sprintf("querystring may not exceed %d bytes", max),
+			Meta:   map[string]any{"max": max},
+		}}
 	}
 
 	options := Options{
-		qs: uqs,
+		Fields: []string{},
+		Filter: map[string][]string{},
+		Page:   map[string]int{},
+		Sort:   []string{},
 	}
 
-	// parse fields
-	options.Fields = parseFields(&uqs)
+	var errs ParseErrors
+
+	// each parameter is decoded separately so that encoded delimiters
+	// (i.e. %26 and %3D) within a value are preserved
+	for rest := qs; rest != ""; {
+		var param string
+		param, rest, _ = strings.Cut(rest, "&")
+		if param == "" {
+			continue
+		}
+
+		rawKey, rawValue, hasValue := strings.Cut(param, "=")
+
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeInvalidEncoding,
+				Parameter: rawKey,
+				Detail:    "parameter is not properly escaped",
+				Err:       err,
+			})
+			continue
+		}
 
-	// parse sort
-	options.Sort = parseSort(&uqs)
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeInvalidEncoding,
+				Parameter: key,
+				Value:     rawValue,
+				Detail:    "value is not properly escaped",
+				Err:       err,
+			})
+			continue
+		}
 
-	// parse filter and page
-	if err := parseBracketParams(uqs, &options); err != nil {
-		return options, err
+		if err := parseParam(key, value, hasValue, &options, c); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	// attempt to infer pagination strategy
-	if _, ok := options.Page["limit"]; ok {
-		options.SetPaginationStrategy(&OffsetStrategy{})
+	if len(options.Filters) > 0 {
+		options.syncFilter()
 	}
 
-	if _, ok := options.Page["size"]; ok {
-		options.SetPaginationStrategy(&PageSizeStrategy{})
+	if len(errs) > 0 {
+		return options, errs
 	}
 
+	inferPaginationStrategy(&options)
+
 	return options, nil
 }
 
-func extract(qs *string, re regexp.Regexp) string {
-	coords := re.FindStringIndex(*qs)
+// inferPaginationStrategy sets the pagination strategy of the Options
+// based on the page parameters provided
+func inferPaginationStrategy(o *Options) {
+	if _, ok := o.Page["limit"]; ok {
+		o.SetPaginationStrategy(&OffsetStrategy{})
+	}
+
+	if _, ok := o.Page["size"]; ok {
+		o.SetPaginationStrategy(&PageSizeStrategy{})
+	}
+
+	// page[number] is 1-based per the JSON:API examples
+	if _, ok := o.Page["number"]; ok {
+		o.SetPaginationStrategy(&PageNumberStrategy{Base: 1})
+	}
+
+	if len(o.Cursor) > 0 {
+		o.SetPaginationStrategy(&CursorStrategy{
+			After:  o.Cursor["after"],
+			Before: o.Cursor["before"],
+		})
+	}
+}
+
+// parseParam applies a single decoded querystring parameter to the Options
+func parseParam(key string, value string, hasValue bool, o *Options, c *Config) *ParseError {
+	switch key {
+	case "fields":
+		if value == "" {
+			return nil
+		}
+
+		names, err := splitNames(key, value)
+		if err != nil {
+			return err
+		}
 
-	var r string
-	if coords != nil {
-		if coords[0] == 0 {
-			// at beginning of string
-			r = (*qs)[coords[0]:coords[1]]
-			*qs = (*qs)[coords[1]:]
-			return r
+		o.Fields = append(o.Fields, names...)
+		return nil
+	case "include":
+		if value == "" {
+			return nil
 		}
 
-		if coords[1] == len(*qs) {
-			// at end of string
-			r = (*qs)[coords[0]:coords[1]]
-			*qs = (*qs)[0:coords[0]]
-			return r
+		var err *ParseError
+		o.Include, err = parseIncludePaths(o.Include, value)
+		return err
+	case "sort":
+		if value == "" {
+			return nil
 		}
 
-		// in middle of string
-		r = (*qs)[coords[0]:coords[1]]
-		*qs = fmt.Sprintf("%s%s", (*qs)[0:coords[0]], (*qs)[coords[1]:])
-		return r
+		names, err := splitNames(key, value)
+		if err != nil {
+			return err
+		}
+
+		o.Sort = append(o.Sort, names...)
+		return nil
+	case "filter":
+		if value == "" {
+			return nil
+		}
+
+		e, err := parseExpression(value, c.maxExpressionDepth())
+		if err != nil {
+			return err
+		}
+
+		// multiple expressions must all be satisfied
+		o.Expression = andExpr(o.Expression, e)
+		return nil
 	}
 
-	return r
-}
+	name, term, ok := splitBracketKey(key)
+	if !ok {
+		// retain any other parameters
+		if o.Extra == nil {
+			o.Extra = url.Values{}
+		}
 
-func parseBracketParams(qs string, o *Options) error {
-	o.Filter = map[string][]string{}
-	o.Page = map[string]int{}
+		o.Extra.Add(key, value)
 
-	terms := bracketRE.FindAllStringSubmatch(qs, -1)
-	values := bracketValueRE.FindAllStringSubmatch(qs, -1)
+		if !hasValue {
+			if o.flags == nil {
+				o.flags = map[string]bool{}
+			}
+
+			o.flags[key] = true
+		}
+
+		return nil
+	}
 
-	if len(terms) > 0 && len(terms) > len(values) {
-		// multiple nested bracket params... not sure how to parse
-		return errors.New("unable to parse: an object hierarchy has been provided")
+	// bracketed parameters without a value are ignored
+	if !hasValue {
+		return nil
 	}
 
-	for i, term := range terms {
-		switch strings.ToLower(term[1]) {
-		case "filter":
-			if o.Filter == nil {
-				o.Filter = map[string][]string{}
+	switch name {
+	case "filter":
+		// check for an operator, i.e. filter[field][operator]
+		field, op, err := splitBracketTerm(key, term)
+		if err != nil {
+			return err
+		}
+
+		// check for array
+		fv := splitValues(value)
+
+		// Filter is derived from the conditions once parsing completes
+		if op == "" {
+			o.Filters = append(o.Filters, legacyConditions(field, fv)...)
+			return nil
+		}
+
+		fc, err := newFilterCondition(field, op, fv)
+		if err != nil {
+			return err
+		}
+
+		o.Filters = append(o.Filters, fc)
+	case "fields":
+		if strings.Contains(term, "][") {
+			return &ParseError{
+				Code:      ErrCodeInvalidHierarchy,
+				Parameter: key,
+				Detail:    "an object hierarchy has been provided",
 			}
+		}
+
+		names, err := splitNames(key, value)
+		if err != nil {
+			return err
+		}
 
-			// check for array
-			if commaRE.MatchString(values[i][1]) {
-				o.Filter[term[2]] = commaRE.Split(values[i][1], -1)
-				continue
+		if o.FieldSets == nil {
+			o.FieldSets = map[string][]string{}
+		}
+
+		o.FieldSets[term] = append(o.FieldSets[term], names...)
+	case "page":
+		if strings.Contains(term, "][") {
+			return &ParseError{
+				Code:      ErrCodeInvalidHierarchy,
+				Parameter: key,
+				Detail:    "an object hierarchy has been provided",
 			}
+		}
 
-			o.Filter[term[2]] = []string{values[i][1]}
-		case "page":
-			if o.Page == nil {
-				o.Page = map[string]int{}
+		// cursors are opaque string values
+		if term == "after" || term == "before" {
+			if o.Cursor == nil {
+				o.Cursor = map[string]string{}
 			}
 
-			v, err := strconv.ParseInt(values[i][1], 0, 64)
-			if err != nil {
-				return err
+			o.Cursor[term] = value
+			return nil
+		}
+
+		v, err := strconv.ParseInt(value, 0, 64)
+		if err != nil {
+			return &ParseError{
+				Code:      ErrCodeInvalidValue,
+				Parameter: key,
+				Value:     value,
+				Detail:    "value must be an integer",
+				Err:       err,
 			}
+		}
 
-			o.Page[term[2]] = int(v)
+		// sizes must be positive and positions must not be negative
+		switch {
+		case (term == "limit" || term == "size") && v < 1:
+			return &ParseError{
+				Code:      ErrCodeInvalidValue,
+				Parameter: key,
+				Value:     value,
+				Detail:    "value must be a positive integer",
+			}
+		case (term == "offset" || term == "page" || term == "number") && v < 0:
+			return &ParseError{
+				Code:      ErrCodeInvalidValue,
+				Parameter: key,
+				Value:     value,
+				Detail:    "value must not be negative",
+			}
 		}
+
+		o.Page[term] = int(v)
 	}
 
 	return nil
 }
 
-func parseFields(qs *string) []string {
-	fields := []string{}
-
-	fieldNames := fieldsRE.FindAllStringSubmatch(extract(qs, *fieldsRE), -1)
-	for fieldNames != nil {
-		for _, field := range fieldNames {
-			// check if sort value is an array
-			if commaRE.MatchString(field[1]) {
-				fa := commaRE.Split(field[1], -1)
-				fields = append(fields, fa...)
-				continue
-			}
+// splitBracketKey separates the name and bracketed term of a filter,
+// fields, page or sort parameter (i.e. "filter" and "age][gte" from
+// filter[age][gte]), ok is false for any other parameter
+func splitBracketKey(key string) (string, string, bool) {
+	i := strings.IndexByte(key, '[')
 
-			fields = append(fields, field[1])
-		}
+	// the term must not be empty
+	if i < 0 || len(key) < i+3 || key[len(key)-1] != ']' {
+		return "", "", false
+	}
 
-		// look for more fields= occurrences
-		fieldNames = fieldsRE.FindAllStringSubmatch(extract(qs, *fieldsRE), -1)
+	switch key[:i] {
+	case "fields", "filter", "page", "sort":
+		return key[:i], key[i+1 : len(key)-1], true
 	}
 
-	return fields
+	return "", "", false
 }
 
-func parseSort(qs *string) []string {
-	sort := []string{}
-
-	fieldNames := sortRE.FindAllStringSubmatch(extract(qs, *sortRE), -1)
-	for fieldNames != nil {
-		for _, field := range fieldNames {
-			// check if sort value is an array
-			if commaRE.MatchString(field[1]) {
-				fa := commaRE.Split(field[1], -1)
-				sort = append(sort, fa...)
-				continue
+// splitBracketTerm separates the field name and optional operator
+// from the contents of a bracketed parameter (i.e. "field][gte")
+func splitBracketTerm(param string, term string) (string, Operator, *ParseError) {
+	parts := strings.Split(term, "][")
+
+	switch len(parts) {
+	case 1:
+		return parts[0], "", nil
+	case 2:
+		op, err := ParseOperator(parts[1])
+		if err != nil {
+			return "", "", &ParseError{
+				Code:      ErrCodeUnsupportedOperator,
+				Parameter: param,
+				Value:     parts[1],
+				Detail:    err.Error(),
+				Err:       err,
 			}
-
-			sort = append(sort, field[1])
 		}
 
-		// look for more sort= occurrences
-		fieldNames = sortRE.FindAllStringSubmatch(extract(qs, *sortRE), -1)
+		return parts[0], op, nil
+	default:
+		return "", "", &ParseError{
+			Code:      ErrCodeInvalidHierarchy,
+			Parameter: param,
+			Detail:    "an object hierarchy has been provided",
+		}
 	}
-
-	return sort
 }
@@ -4,7 +4,9 @@
 package options
 
 import (
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -30,11 +32,11 @@ func TestFromQuerystring(t *testing.T) {
 			"multiple filters not repeated",
 			args{qs: "filter[fieldA]=value1&filter[fieldB]=value2"},
 			Options{
-				qs:     "filter[fieldA]=value1&filter[fieldB]=value2",
-				Fields: []string{},
-				Filter: map[string][]string{"fieldA": {"value1"}, "fieldB": {"value2"}},
-				Page:   map[string]int{},
-				Sort:   []string{},
+				Fields:  []string{},
+				Filter:  map[string][]string{"fieldA": {"value1"}, "fieldB": {"value2"}},
+				Filters: []FilterCondition{{"fieldA", OpEq, []string{"value1"}}, {"fieldB", OpEq, []string{"value2"}}},
+				Page:    map[string]int{},
+				Sort:    []string{},
 			},
 			false,
 		},
@@ -42,11 +44,11 @@ func TestFromQuerystring(t *testing.T) {
 			"multiple filters not repeated and page",
 			args{qs: "filter[fieldA]=value1&filter[fieldB]=value2&page[offset]=100"},
 			Options{
-				qs:     "filter[fieldA]=value1&filter[fieldB]=value2&page[offset]=100",
-				Fields: []string{},
-				Filter: map[string][]string{"fieldA": {"value1"}, "fieldB": {"value2"}},
-				Page:   map[string]int{"offset": 100},
-				Sort:   []string{},
+				Fields:  []string{},
+				Filter:  map[string][]string{"fieldA": {"value1"}, "fieldB": {"value2"}},
+				Filters: []FilterCondition{{"fieldA", OpEq, []string{"value1"}}, {"fieldB", OpEq, []string{"value2"}}},
+				Page:    map[string]int{"offset": 100},
+				Sort:    []string{},
 			},
 			false,
 		},
@@ -54,11 +56,11 @@ func TestFromQuerystring(t *testing.T) {
 			"filters and fields A",
 			args{qs: "filter[fieldB]=value1&fields=fieldA,fieldB"},
 			Options{
-				qs:     "filter[fieldB]=value1&fields=fieldA,fieldB",
-				Fields: []string{"fieldA", "fieldB"},
-				Filter: map[string][]string{"fieldB": {"value1"}},
-				Page:   map[string]int{},
-				Sort:   []string{},
+				Fields:  []string{"fieldA", "fieldB"},
+				Filter:  map[string][]string{"fieldB": {"value1"}},
+				Filters: []FilterCondition{{"fieldB", OpEq, []string{"value1"}}},
+				Page:    map[string]int{},
+				Sort:    []string{},
 			},
 			false,
 		},
@@ -66,11 +68,11 @@ func TestFromQuerystring(t *testing.T) {
 			"filters and fields B",
 			args{qs: "fields=fieldA,fieldB&filter[fieldB]=value1"},
 			Options{
-				qs:     "fields=fieldA,fieldB&filter[fieldB]=value1",
-				Fields: []string{"fieldA", "fieldB"},
-				Filter: map[string][]string{"fieldB": {"value1"}},
-				Page:   map[string]int{},
-				Sort:   []string{},
+				Fields:  []string{"fieldA", "fieldB"},
+				Filter:  map[string][]string{"fieldB": {"value1"}},
+				Filters: []FilterCondition{{"fieldB", OpEq, []string{"value1"}}},
+				Page:    map[string]int{},
+				Sort:    []string{},
 			},
 			false,
 		},
@@ -78,11 +80,11 @@ func TestFromQuerystring(t *testing.T) {
 			"filters and fields C",
 			args{qs: "fields=fieldA,fieldB&filter[fieldB]=value1&fields=fieldC"},
 			Options{
-				qs:     "fields=fieldA,fieldB&filter[fieldB]=value1&fields=fieldC",
-				Fields: []string{"fieldA", "fieldB", "fieldC"},
-				Filter: map[string][]string{"fieldB": {"value1"}},
-				Page:   map[string]int{},
-				Sort:   []string{},
+				Fields:  []string{"fieldA", "fieldB", "fieldC"},
+				Filter:  map[string][]string{"fieldB": {"value1"}},
+				Filters: []FilterCondition{{"fieldB", OpEq, []string{"value1"}}},
+				Page:    map[string]int{},
+				Sort:    []string{},
 			},
 			false,
 		},
@@ -90,7 +92,6 @@ func TestFromQuerystring(t *testing.T) {
 			"single sort",
 			args{qs: "sort=fieldA"},
 			Options{
-				qs:     "sort=fieldA",
 				Fields: []string{},
 				Filter: map[string][]string{},
 				Page:   map[string]int{},
@@ -102,7 +103,6 @@ func TestFromQuerystring(t *testing.T) {
 			"multiple sort parameters",
 			args{qs: "sort=fieldA&sort=fieldB&sort=fieldC"},
 			Options{
-				qs:     "sort=fieldA&sort=fieldB&sort=fieldC",
 				Fields: []string{},
 				Filter: map[string][]string{},
 				Page:   map[string]int{},
@@ -114,7 +114,6 @@ func TestFromQuerystring(t *testing.T) {
 			"multiple fields via one parameter",
 			args{qs: "sort=fieldA,fieldB,fieldC"},
 			Options{
-				qs:     "sort=fieldA,fieldB,fieldC",
 				Fields: []string{},
 				Filter: map[string][]string{},
 				Page:   map[string]int{},
@@ -129,12 +128,12 @@ func TestFromQuerystring(t *testing.T) {
 				qs: "filter[fieldA]=value1,value2&filter[fieldB]=*test&page[offset]=10&page[limit]=10&sort=-fieldA,fieldB",
 			},
 			Options{
-				ps:     offsetPS,
-				qs:     "filter[fieldA]=value1,value2&filter[fieldB]=*test&page[offset]=10&page[limit]=10&sort=-fieldA,fieldB",
-				Fields: []string{},
-				Filter: map[string][]string{"fieldA": {"value1", "value2"}, "fieldB": {"*test"}},
-				Page:   map[string]int{"offset": 10, "limit": 10},
-				Sort:   []string{"-fieldA", "fieldB"},
+				ps:      offsetPS,
+				Fields:  []string{},
+				Filter:  map[string][]string{"fieldA": {"value1", "value2"}, "fieldB": {"*test"}},
+				Filters: []FilterCondition{{"fieldA", OpIn, []string{"value1", "value2"}}, {"fieldB", OpEq, []string{"*test"}}},
+				Page:    map[string]int{"offset": 10, "limit": 10},
+				Sort:    []string{"-fieldA", "fieldB"},
 			},
 			false,
 		},
@@ -146,7 +145,6 @@ func TestFromQuerystring(t *testing.T) {
 			},
 			Options{
 				ps:     offsetPS,
-				qs:     "page[limit]=10&page[offset]=10",
 				Fields: []string{},
 				Filter: map[string][]string{},
 				Page:   map[string]int{"offset": 10, "limit": 10},
@@ -156,7 +154,6 @@ func TestFromQuerystring(t *testing.T) {
 		},
 		{
 			"no filters, no sorting, but fields", args{qs: "fields=fieldA,-fieldB"}, Options{
-				qs:     "fields=fieldA,-fieldB",
 				Fields: []string{"fieldA", "-fieldB"},
 				Filter: map[string][]string{},
 				Page:   map[string]int{},
@@ -166,7 +163,6 @@ func TestFromQuerystring(t *testing.T) {
 		},
 		{
 			"no filters, no sorting, but fields in multiple params", args{qs: "fields=fieldA,-fieldB&fields=fieldC"}, Options{
-				qs:     "fields=fieldA,-fieldB&fields=fieldC",
 				Fields: []string{"fieldA", "-fieldB", "fieldC"},
 				Filter: map[string][]string{},
 				Page:   map[string]int{},
@@ -181,7 +177,6 @@ func TestFromQuerystring(t *testing.T) {
 				qs: "page%5Blimit%5D=10&page%5Boffset%5D=10",
 			}, Options{
 				ps:     offsetPS,
-				qs:     "page[limit]=10&page[offset]=10",
 				Fields: []string{},
 				Filter: map[string][]string{},
 				Page:   map[string]int{"offset": 10, "limit": 10},
@@ -191,7 +186,6 @@ func TestFromQuerystring(t *testing.T) {
 		},
 		{
 			"filters with lt, lte, gt and gte clauses", args{qs: "filter[iVal1]=%3C4&filter[iVal2]=%3C%3D3&filter[iVal3]=%3E1&filter[iVal4]=%3E%3D2"}, Options{
-				qs:     "filter[iVal1]=<4&filter[iVal2]=<=3&filter[iVal3]=>1&filter[iVal4]=>=2",
 				Fields: []string{},
 				Filter: map[string][]string{
 					"iVal1": {"<4"},
@@ -199,6 +193,12 @@ func TestFromQuerystring(t *testing.T) {
 					"iVal3": {">1"},
 					"iVal4": {">=2"},
 				},
+				Filters: []FilterCondition{
+					{"iVal1", OpLt, []string{"4"}},
+					{"iVal2", OpLte, []string{"3"}},
+					{"iVal3", OpGt, []string{"1"}},
+					{"iVal4", OpGte, []string{"2"}},
+				},
 				Page: map[string]int{},
 				Sort: []string{},
 			},
@@ -210,11 +210,11 @@ func TestFromQuerystring(t *testing.T) {
 				qs: "filter[empty]=",
 			},
 			Options{
-				qs:     "filter[empty]=",
-				Fields: []string{},
-				Filter: map[string][]string{"empty": {""}},
-				Page:   map[string]int{},
-				Sort:   []string{},
+				Fields:  []string{},
+				Filter:  map[string][]string{"empty": {""}},
+				Filters: []FilterCondition{{"empty", OpEq, []string{""}}},
+				Page:    map[string]int{},
+				Sort:    []string{},
 			},
 			false,
 		},
@@ -224,13 +224,17 @@ func TestFromQuerystring(t *testing.T) {
 				qs: "filter[test]=value&filter[empty]=&filter[other]=test",
 			},
 			Options{
-				qs:     "filter[test]=value&filter[empty]=&filter[other]=test",
 				Fields: []string{},
 				Filter: map[string][]string{
 					"empty": {""},
 					"other": {"test"},
 					"test":  {"value"},
 				},
+				Filters: []FilterCondition{
+					{"test", OpEq, []string{"value"}},
+					{"empty", OpEq, []string{""}},
+					{"other", OpEq, []string{"test"}},
+				},
 				Page: map[string]int{},
 				Sort: []string{},
 			},
@@ -242,14 +246,14 @@ func TestFromQuerystring(t *testing.T) {
 				qs: "filter[test]=value&filter[empty]&filter[other]=test",
 			},
 			Options{
-				qs:     "filter[test]=value&filter[empty]&filter[other]=test",
 				Fields: []string{},
 				Filter: map[string][]string{
 					"other": {"test"},
 					"test":  {"value"},
 				},
-				Page: map[string]int{},
-				Sort: []string{},
+				Filters: []FilterCondition{{"test", OpEq, []string{"value"}}, {"other", OpEq, []string{"test"}}},
+				Page:    map[string]int{},
+				Sort:    []string{},
 			},
 			false,
 		},
@@ -257,11 +261,12 @@ func TestFromQuerystring(t *testing.T) {
 			"extra non-filter parameter at end with multiple filters not repeated",
 			args{qs: "filter[fieldA]=value1&filter[fieldB]=value2&something=blah"},
 			Options{
-				qs:     "filter[fieldA]=value1&filter[fieldB]=value2&something=blah",
-				Fields: []string{},
-				Filter: map[string][]string{"fieldA": {"value1"}, "fieldB": {"value2"}},
-				Page:   map[string]int{},
-				Sort:   []string{},
+				Extra:   url.Values{"something": {"blah"}},
+				Fields:  []string{},
+				Filter:  map[string][]string{"fieldA": {"value1"}, "fieldB": {"value2"}},
+				Filters: []FilterCondition{{"fieldA", OpEq, []string{"value1"}}, {"fieldB", OpEq, []string{"value2"}}},
+				Page:    map[string]int{},
+				Sort:    []string{},
 			},
 			false,
 		},
@@ -269,12 +274,141 @@ func TestFromQuerystring(t *testing.T) {
 			"extra non-filter parameter at beginning with multiple filters not repeated",
 			args{qs: "something=blah&filter[fieldA]=value1&filter[fieldB]=value2"},
 			Options{
-				qs:     "something=blah&filter[fieldA]=value1&filter[fieldB]=value2",
+				Extra:   url.Values{"something": {"blah"}},
+				Fields:  []string{},
+				Filter:  map[string][]string{"fieldA": {"value1"}, "fieldB": {"value2"}},
+				Filters: []FilterCondition{{"fieldA", OpEq, []string{"value1"}}, {"fieldB", OpEq, []string{"value2"}}},
+				Page:    map[string]int{},
+				Sort:    []string{},
+			},
+			false,
+		},
+		{
+			"filters with operators",
+			args{qs: "filter[age][gte]=21&filter[age][lt]=65&filter[status][in]=open,closed&filter[deleted][null]=true"},
+			Options{
+				Fields: []string{},
+				Filter: map[string][]string{
+					"age":    {">=21", "<65"},
+					"status": {"open", "closed"},
+				},
+				Filters: []FilterCondition{
+					{"age", OpGte, []string{"21"}},
+					{"age", OpLt, []string{"65"}},
+					{"status", OpIn, []string{"open", "closed"}},
+					{"deleted", OpNull, []string{"true"}},
+				},
+				Page: map[string]int{},
+				Sort: []string{},
+			},
+			false,
+		},
+		{
+			"filter with between operator",
+			args{qs: "filter[created][between]=2020-01-01,2020-12-31&filter[name][nin]=a,b"},
+			Options{
+				Fields: []string{},
+				Filter: map[string][]string{},
+				Filters: []FilterCondition{
+					{"created", OpBetween, []string{"2020-01-01", "2020-12-31"}},
+					{"name", OpNin, []string{"a", "b"}},
+				},
+				Page: map[string]int{},
+				Sort: []string{},
+			},
+			false,
+		},
+		{
+			"repeated filters for a field",
+			args{qs: "filter[a]=1&filter[a]=2&filter[age][gte]=21&filter[age]=30"},
+			Options{
+				Fields: []string{},
+				Filter: map[string][]string{
+					"a":   {"1", "2"},
+					"age": {">=21", "30"},
+				},
+				Filters: []FilterCondition{
+					{"a", OpEq, []string{"1"}},
+					{"a", OpEq, []string{"2"}},
+					{"age", OpGte, []string{"21"}},
+					{"age", OpEq, []string{"30"}},
+				},
+				Page: map[string]int{},
+				Sort: []string{},
+			},
+			false,
+		},
+		{
+			"filter with unsupported operator",
+			args{qs: "filter[age][around]=21"},
+			Options{
+				Fields: []string{},
+				Filter: map[string][]string{},
+				Page:   map[string]int{},
+				Sort:   []string{},
+			},
+			true,
+		},
+		{
+			"filter with between operator and a single value",
+			args{qs: "filter[age][between]=21"},
+			Options{
 				Fields: []string{},
-				Filter: map[string][]string{"fieldA": {"value1"}, "fieldB": {"value2"}},
+				Filter: map[string][]string{},
 				Page:   map[string]int{},
 				Sort:   []string{},
 			},
+			true,
+		},
+		{
+			"filter with nested object hierarchy",
+			args{qs: "filter[author][name][eq]=test"},
+			Options{
+				Fields: []string{},
+				Filter: map[string][]string{},
+				Page:   map[string]int{},
+				Sort:   []string{},
+			},
+			true,
+		},
+		{
+			"sparse fieldsets per type",
+			args{qs: "fields[articles]=title,body&fields[people]=name&fields=id"},
+			Options{
+				Fields: []string{"id"},
+				FieldSets: map[string][]string{
+					"articles": {"title", "body"},
+					"people":   {"name"},
+				},
+				Filter: map[string][]string{},
+				Page:   map[string]int{},
+				Sort:   []string{},
+			},
+			false,
+		},
+		{
+			"sparse fieldsets in multiple params",
+			args{qs: "fields[articles]=title&fields[articles]=body"},
+			Options{
+				Fields:    []string{},
+				FieldSets: map[string][]string{"articles": {"title", "body"}},
+				Filter:    map[string][]string{},
+				Page:      map[string]int{},
+				Sort:      []string{},
+			},
+			false,
+		},
+		{
+			"extra parameters with include and sort",
+			args{qs: "q=search&include=author&tracking=a&sort=-created&tracking=b"},
+			Options{
+				Extra:   url.Values{"q": {"search"}, "tracking": {"a", "b"}},
+				Fields:  []string{},
+				Filter:  map[string][]string{},
+				Include: []Relationship{{Name: "author"}},
+				Page:    map[string]int{},
+				Sort:    []string{"-created"},
+			},
 			false,
 		},
 	}
@@ -290,9 +424,109 @@ func TestFromQuerystring(t *testing.T) {
 				return
 			}
 
+			// the parsed Filter values are in sync with Filters
+			if len(tt.want.Filter) > 0 {
+				tt.want.synced = tt.want.Filter
+			}
+
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("FromQuerystring()\ngot:\n\t%+v\n\nwant:\n\n\t%+v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_splitBracketKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		wantName string
+		wantTerm string
+		wantOK   bool
+	}{
+		{"filter[name]", "filter", "name", true},
+		{"filter[age][gte]", "filter", "age][gte", true},
+		{"fields[people]", "fields", "people", true},
+		{"page[limit]", "page", "limit", true},
+		{"sort[name]", "sort", "name", true},
+		{"filter[]", "", "", false},
+		{"filter[name", "", "", false},
+		{"filter", "", "", false},
+		{"other[name]", "", "", false},
+		{"xfilter[name]", "", "", false},
+		{"filter[a]b]", "filter", "a]b", true},
+		{"[name]", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			name, term, ok := splitBracketKey(tt.key)
+			if name != tt.wantName || term != tt.wantTerm || ok != tt.wantOK {
+				t.Errorf("splitBracketKey() = %q, %q, %v, want %q, %q, %v", name, term, ok, tt.wantName, tt.wantTerm, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFromQuerystringWithConfig_parseLimits(t *testing.T) {
+	deep := "filter=" + url.QueryEscape(strings.Repeat("(", 20)+"a eq 1"+strings.Repeat(")", 20))
+
+	tests := []struct {
+		name     string
+		qs       string
+		c        Config
+		wantCode ErrorCode
+	}{
+		{"within size", "filter[name]=value&sort=name", Config{MaxQuerystringSize: 32}, ""},
+		{"size exceeded", "filter[name]=value&sort=name&page[limit]=10", Config{MaxQuerystringSize: 32}, ErrCodeLimitExceeded},
+		{"default size exceeded", strings.Repeat("sort=name&", 2000), Config{}, ErrCodeLimitExceeded},
+		{"size not limited", strings.Repeat("sort=name&", 2000), Config{MaxQuerystringSize: -1}, ""},
+		{"default depth exceeded", deep, Config{}, ErrCodeExpressionTooDeep},
+		{"depth exceeded", "filter=not+(a+eq+1)", Config{MaxExpressionDepth: 1}, ErrCodeExpressionTooDeep},
+		{"depth not limited", deep, Config{MaxExpressionDepth: -1}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromQuerystringWithConfig(tt.qs, tt.c)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("FromQuerystringWithConfig() error = %v", err)
+				}
+
+				return
+			}
+
+			pes, ok := err.(ParseErrors)
+			if !ok || len(pes) != 1 || pes[0].Code != tt.wantCode {
+				t.Errorf("FromQuerystringWithConfig() error = %v, want %s", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+var benchmarkQuerystrings = []struct {
+	name string
+	qs   string
+}{
+	{"simple", "filter[status]=open&page[limit]=10&page[offset]=20&sort=-created"},
+	{
+		"typical",
+		"filter[status]=open,closed&filter[age][gte]=21&filter[name][like]=Sm*th&fields=id,name,age" +
+			"&fields[people]=name,email&include=author,comments.author&page[size]=25&page[page]=3&sort=-created,name&q=search",
+	},
+	{"encoded", "filter[customer]=Smith%20%26%20Sons&filter[name]=%22Doe,%20Jane%22,Roe&q=a%3Db&sort=-name"},
+	{"repeated", strings.Repeat("fields=a,b,c&sort=-d,e&", 100)},
+}
+
+func BenchmarkFromQuerystring(b *testing.B) {
+	for _, bq := range benchmarkQuerystrings {
+		b.Run(bq.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(bq.qs)))
+
+			for i := 0; i < b.N; i++ {
+				if _, err := FromQuerystring(bq.qs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
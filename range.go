@@ -0,0 +1,190 @@
+package options
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RangeUnit is the default unit of Range and Content-Range headers
+const RangeUnit = "items"
+
+// ErrUnsupportedRangeUnit is returned by SetRange when the unit of a
+// Range header is not supported, such a header should be ignored
+var ErrUnsupportedRangeUnit = errors.New("unsupported range unit")
+
+// RangeStrategy is a pagination strategy for ranges provided via the
+// Range header (i.e. Range: items=0-24), the current range is held in
+// page[offset] and page[limit] so that links are generated in the same
+// manner as OffsetStrategy
+type RangeStrategy struct {
+	OffsetStrategy
+
+	// Unit is the range unit, RangeUnit is used when empty
+	Unit string
+}
+
+// Range returns a Range header value for the current page
+func (rs RangeStrategy) Range(c map[string]int) string {
+	l, o, ok := rs.LimitOffset(c)
+	if !ok || l <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s=%d-%d", rs.unit(), o, o+l-1)
+}
+
+func (rs RangeStrategy) unit() string {
+	if rs.Unit == "" {
+		return RangeUnit
+	}
+
+	return rs.Unit
+}
+
+// ParseRange reads a single range (i.e. items=0-24) from a Range header
+// value and returns the unit, limit and offset it describes
+func ParseRange(header string) (string, int, int, error) {
+	unit, spec, ok := strings.Cut(strings.TrimSpace(header), "=")
+	if !ok || unit == "" {
+		return "", 0, 0, fmt.Errorf("invalid range: %q", header)
+	}
+
+	if strings.Contains(spec, ",") {
+		return "", 0, 0, fmt.Errorf("invalid range: multiple ranges are not supported")
+	}
+
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok || first == "" || last == "" {
+		return "", 0, 0, fmt.Errorf("invalid range: %q must include a first and last position", spec)
+	}
+
+	start, err := strconv.Atoi(first)
+	if err != nil || start < 0 {
+		return "", 0, 0, fmt.Errorf("invalid range: %q is not a valid position", first)
+	}
+
+	end, err := strconv.Atoi(last)
+	if err != nil || end < start {
+		return "", 0, 0, fmt.Errorf("invalid range: %q is not a valid position", last)
+	}
+
+	return unit, end - start + 1, start, nil
+}
+
+// SetRange applies the range of the provided Range header value to the
+// Options, configuring a RangeStrategy, the unit of the range must be
+// RangeUnit or one of the provided units
+func (o *Options) SetRange(header string, units ...string) error {
+	// the unit is checked first so that other ranges (i.e. bytes) are
+	// ignored rather than rejected
+	unit, _, _ := strings.Cut(strings.TrimSpace(header), "=")
+	if !supportedRangeUnit(unit, units) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedRangeUnit, unit)
+	}
+
+	unit, limit, offset, err := ParseRange(header)
+	if err != nil {
+		return err
+	}
+
+	if o.Page == nil {
+		o.Page = map[string]int{}
+	}
+
+	o.Page["limit"] = limit
+	o.Page["offset"] = offset
+	o.SetPaginationStrategy(&RangeStrategy{Unit: unit})
+
+	return nil
+}
+
+// Range returns a Range header value for the current page when the
+// Options use a RangeStrategy
+func (o Options) Range() string {
+	if rs, ok := o.rangeStrategy(); ok {
+		return rs.Range(o.Page)
+	}
+
+	return ""
+}
+
+// ContentRange returns a Content-Range header value (i.e. items 0-24/300)
+// for the current page and the provided total, when the total is not known
+// a negative value may be provided (i.e. items 0-24/*)
+func (o Options) ContentRange(total int) string {
+	unit := RangeUnit
+	if rs, ok := o.rangeStrategy(); ok {
+		unit = rs.unit()
+	}
+
+	size := "*"
+	if total >= 0 {
+		size = strconv.Itoa(total)
+	}
+
+	pi := o.PageInfo(total)
+	if total < 0 {
+		pi = o.PageInfo(pi.Offset + pi.Limit)
+	}
+
+	// the page contains no items
+	if pi.LastItem == 0 {
+		return fmt.Sprintf("%s */%s", unit, size)
+	}
+
+	return fmt.Sprintf("%s %d-%d/%s", unit, pi.FirstItem-1, pi.LastItem-1, size)
+}
+
+// RangeStatus returns the HTTP status for a response to the current page:
+// 206 (Partial Content) when the page contains a portion of the items, 416
+// (Range Not Satisfiable) when the page begins beyond the total, otherwise
+// 200 (OK), when the total is not known a page is considered partial
+func (o Options) RangeStatus(total int) int {
+	if total < 0 {
+		if _, _, ok := o.LimitOffset(); ok {
+			return http.StatusPartialContent
+		}
+
+		return http.StatusOK
+	}
+
+	pi := o.PageInfo(total)
+
+	switch {
+	case pi.Offset > 0 && pi.Offset >= total:
+		return http.StatusRequestedRangeNotSatisfiable
+	case pi.HasPrev || pi.HasNext:
+		return http.StatusPartialContent
+	default:
+		return http.StatusOK
+	}
+}
+
+func supportedRangeUnit(unit string, units []string) bool {
+	if len(units) == 0 {
+		units = []string{RangeUnit}
+	}
+
+	// range units are case-insensitive
+	for _, u := range units {
+		if strings.EqualFold(unit, u) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o Options) rangeStrategy() (*RangeStrategy, bool) {
+	switch rs := o.ps.(type) {
+	case *RangeStrategy:
+		return rs, true
+	case RangeStrategy:
+		return &rs, true
+	}
+
+	return nil, false
+}
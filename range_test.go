@@ -0,0 +1,128 @@
+package options
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantUnit   string
+		wantLimit  int
+		wantOffset int
+		wantErr    bool
+	}{
+		{"first page", "items=0-24", "items", 25, 0, false},
+		{"subsequent page", "items=25-49", "items", 25, 25, false},
+		{"single item", " rows=7-7", "rows", 1, 7, false},
+		{"missing unit", "0-24", "", 0, 0, true},
+		{"open ended", "items=25-", "", 0, 0, true},
+		{"suffix", "items=-25", "", 0, 0, true},
+		{"multiple ranges", "items=0-4,10-14", "", 0, 0, true},
+		{"reversed", "items=24-0", "", 0, 0, true},
+		{"not an integer", "items=a-b", "", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit, limit, offset, err := ParseRange(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if unit != tt.wantUnit || limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("ParseRange() = %v, %v, %v, want %v, %v, %v", unit, limit, offset, tt.wantUnit, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestOptions_SetRange(t *testing.T) {
+	o, err := FromQuerystring("sort=name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.SetRange("items=25-49"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := o.Range(); got != "items=25-49" {
+		t.Errorf("Options.Range() = %v, want items=25-49", got)
+	}
+
+	if l, off, ok := o.LimitOffset(); !ok || l != 25 || off != 25 {
+		t.Errorf("Options.LimitOffset() = %d, %d, %v, want 25, 25, true", l, off, ok)
+	}
+
+	if got, want := o.Next(), "page[limit]=25&page[offset]=50&sort=name"; got != want {
+		t.Errorf("Options.Next() = %v, want %v", got, want)
+	}
+
+	if err := o.SetRange("items=25-"); err == nil {
+		t.Error("Options.SetRange() expected an error for an open ended range")
+	}
+}
+
+func TestOptions_SetRange_unit(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		units   []string
+		wantErr error
+	}{
+		{"default unit", "Items=0-9", nil, nil},
+		{"unsupported unit", "bytes=0-1023", nil, ErrUnsupportedRangeUnit},
+		{"configured unit", "rows=0-9", []string{"rows"}, nil},
+		{"default unit not configured", "items=0-9", []string{"rows"}, ErrUnsupportedRangeUnit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var o Options
+
+			err := o.SetRange(tt.header, tt.units...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Options.SetRange() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil && (len(o.Page) > 0 || o.PaginationStrategy() != nil) {
+				t.Errorf("Options.SetRange() page = %v, want no page", o.Page)
+			}
+		})
+	}
+}
+
+func TestOptions_ContentRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		total      int
+		want       string
+		wantStatus int
+	}{
+		{"first page", "items=0-24", 300, "items 0-24/300", http.StatusPartialContent},
+		{"last partial page", "items=275-299", 290, "items 275-289/290", http.StatusPartialContent},
+		{"all items", "items=0-24", 20, "items 0-19/20", http.StatusOK},
+		{"beyond the total", "items=300-324", 290, "items */290", http.StatusRequestedRangeNotSatisfiable},
+		{"no items", "items=0-24", 0, "items */0", http.StatusOK},
+		{"unknown total", "rows=50-74", -1, "rows 50-74/*", http.StatusPartialContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := Options{}
+			if err := o.SetRange(tt.header, RangeUnit, "rows"); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := o.ContentRange(tt.total); got != tt.want {
+				t.Errorf("Options.ContentRange() = %v, want %v", got, tt.want)
+			}
+
+			if got := o.RangeStatus(tt.total); got != tt.wantStatus {
+				t.Errorf("Options.RangeStatus() = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
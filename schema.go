@@ -0,0 +1,221 @@
+package options
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Schema declares which fields may be filtered, sorted and selected
+// via the querystring, keyed by the field name used in the querystring
+type Schema struct {
+	Fields map[string]FieldSchema
+
+	// MaxIncludeDepth limits the number of relationships in a single
+	// include path (i.e. comments.author has a depth of 2), when 0
+	// the depth is not limited
+	MaxIncludeDepth int
+
+	// Relationships lists the dot-separated relationship paths that may
+	// be provided via the include parameter, along with any path that
+	// is a prefix of one listed
+	Relationships []string
+}
+
+// FieldSchema describes what a client may do with a single field
+type FieldSchema struct {
+	Filterable bool
+	Sortable   bool
+	Selectable bool
+
+	// Operators restricts the filter operators permitted for the field,
+	// when empty all operators are permitted
+	Operators []Operator
+
+	// Column is the database column of the field, when it differs from
+	// the field name
+	Column string
+}
+
+// FromQuerystringWithSchema parses an Options object from the provided
+// querystring and validates the filters, sorting and fields against the
+// provided Schema
+func FromQuerystringWithSchema(qs string, s Schema) (Options, error) {
+	o, err := FromQuerystring(qs)
+	if err != nil {
+		return o, err
+	}
+
+	if err := s.Validate(o); err != nil {
+		return o, err
+	}
+
+	return o, nil
+}
+
+// Validate confirms that each filter, sort and field provided in the
+// Options is permitted by the Schema, returning ParseErrors when one or
+// more are not
+func (s Schema) Validate(o Options) error {
+	var errs ParseErrors
+
+	// filters
+	for _, fc := range o.Conditions() {
+		param := fmt.Sprintf("filter[%s]", fc.Field)
+		fs, ok := s.Fields[fc.Field]
+		if !ok || !fs.Filterable {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeNotFilterable,
+				Parameter: param,
+				Value:     fc.Field,
+				Detail:    "field is not filterable",
+			})
+			continue
+		}
+
+		if !fs.permits(fc.Operator) {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeOperatorNotPermitted,
+				Parameter: fmt.Sprintf("%s[%s]", param, fc.Operator),
+				Value:     string(fc.Operator),
+				Detail:    fmt.Sprintf("operator %q is not permitted", fc.Operator),
+			})
+		}
+	}
+
+	// filter expression
+	for _, fc := range exprConditions(o.Expression) {
+		fs, ok := s.Fields[fc.Field]
+		if !ok || !fs.Filterable {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeNotFilterable,
+				Parameter: "filter",
+				Value:     fc.Field,
+				Detail:    fmt.Sprintf("field %q is not filterable", fc.Field),
+			})
+			continue
+		}
+
+		if !fs.permits(fc.Operator) {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeOperatorNotPermitted,
+				Parameter: "filter",
+				Value:     string(fc.Operator),
+				Detail:    fmt.Sprintf("operator %q is not permitted for field %q", fc.Operator, fc.Field),
+			})
+		}
+	}
+
+	// sorting
+	for _, field := range o.Sort {
+		name := trimPrefix(field)
+		if fs, ok := s.Fields[name]; !ok || !fs.Sortable {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeNotSortable,
+				Parameter: "sort",
+				Value:     name,
+				Detail:    fmt.Sprintf("field %q is not sortable", name),
+			})
+		}
+	}
+
+	// field projections
+	for _, field := range o.Fields {
+		name := trimPrefix(field)
+		if fs, ok := s.Fields[name]; !ok || !fs.Selectable {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeNotSelectable,
+				Parameter: "fields",
+				Value:     name,
+				Detail:    fmt.Sprintf("field %q is not selectable", name),
+			})
+		}
+	}
+
+	// sparse fieldsets are validated against the same fields
+	types := make([]string, 0, len(o.FieldSets))
+	for typ := range o.FieldSets {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+
+	for _, typ := range types {
+		for _, field := range o.FieldSets[typ] {
+			name := trimPrefix(field)
+			if fs, ok := s.Fields[name]; !ok || !fs.Selectable {
+				errs = append(errs, &ParseError{
+					Code:      ErrCodeNotSelectable,
+					Parameter: fmt.Sprintf("fields[%s]", typ),
+					Value:     name,
+					Detail:    fmt.Sprintf("field %q is not selectable", name),
+				})
+			}
+		}
+	}
+
+	// relationships
+	for _, path := range o.IncludePaths() {
+		if s.MaxIncludeDepth > 0 && strings.Count(path, ".") >= s.MaxIncludeDepth {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeIncludeTooDeep,
+				Parameter: "include",
+				Value:     path,
+				Detail:    fmt.Sprintf("relationship paths may not exceed a depth of %d", s.MaxIncludeDepth),
+			})
+			continue
+		}
+
+		if !s.includable(path) {
+			errs = append(errs, &ParseError{
+				Code:      ErrCodeNotIncludable,
+				Parameter: "include",
+				Value:     path,
+				Detail:    fmt.Sprintf("relationship %q may not be included", path),
+			})
+		}
+	}
+
+	return errs.errs()
+}
+
+// Columns returns the database column of each filterable or sortable
+// field keyed by field name, suitable for use with sqlbuilder.New
+func (s Schema) Columns() map[string]string {
+	columns := map[string]string{}
+	for name, fs := range s.Fields {
+		if !fs.Filterable && !fs.Sortable {
+			continue
+		}
+
+		columns[name] = name
+		if fs.Column != "" {
+			columns[name] = fs.Column
+		}
+	}
+
+	return columns
+}
+
+func (s Schema) includable(path string) bool {
+	for _, r := range s.Relationships {
+		if r == path || strings.HasPrefix(r, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (fs FieldSchema) permits(op Operator) bool {
+	if len(fs.Operators) == 0 {
+		return true
+	}
+
+	for _, o := range fs.Operators {
+		if o == op {
+			return true
+		}
+	}
+
+	return false
+}
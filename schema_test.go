@@ -0,0 +1,80 @@
+package options
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFromQuerystringWithSchema(t *testing.T) {
+	s := Schema{
+		Fields: map[string]FieldSchema{
+			"name":   {Filterable: true, Sortable: true, Selectable: true},
+			"age":    {Filterable: true, Sortable: true, Operators: []Operator{OpEq, OpGte, OpLte}},
+			"secret": {},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		qs      string
+		wantErr error
+	}{
+		{"empty querystring", "", nil},
+		{"permitted filters, sorting and fields", "filter[name]=test&filter[age][gte]=21&sort=-age,name&fields=name", nil},
+		{"legacy comparison prefix", "filter[age]=%3E%3D21", nil},
+		{
+			"invalid filter operator",
+			"filter[age][around]=21",
+			ParseErrors{{Code: ErrCodeUnsupportedOperator, Parameter: "filter[age][around]", Value: "around", Detail: `unsupported filter operator "around"`, Err: errors.New(`unsupported filter operator "around"`)}},
+		},
+		{
+			"unknown filter field",
+			"filter[unknown]=test",
+			ParseErrors{{Code: ErrCodeNotFilterable, Parameter: "filter[unknown]", Value: "unknown", Detail: "field is not filterable"}},
+		},
+		{
+			"operator not permitted",
+			"filter[age][like]=2*",
+			ParseErrors{{Code: ErrCodeOperatorNotPermitted, Parameter: "filter[age][like]", Value: "like", Detail: `operator "like" is not permitted`}},
+		},
+		{
+			"field not sortable or selectable",
+			"sort=secret&fields=name,-age",
+			ParseErrors{
+				{Code: ErrCodeNotSortable, Parameter: "sort", Value: "secret", Detail: `field "secret" is not sortable`},
+				{Code: ErrCodeNotSelectable, Parameter: "fields", Value: "age", Detail: `field "age" is not selectable`},
+			},
+		},
+		{
+			"field not selectable in a sparse fieldset",
+			"fields[people]=name,secret&fields[pets]=name",
+			ParseErrors{{Code: ErrCodeNotSelectable, Parameter: "fields[people]", Value: "secret", Detail: `field "secret" is not selectable`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromQuerystringWithSchema(tt.qs, s)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("FromQuerystringWithSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	s := Schema{
+		Fields: map[string]FieldSchema{
+			"fieldA": {Filterable: true},
+		},
+	}
+
+	o := Options{
+		Filter: map[string][]string{"fieldB": {"value"}, "fieldA": {"value"}},
+	}
+
+	want := ParseErrors{{Code: ErrCodeNotFilterable, Parameter: "filter[fieldB]", Value: "fieldB", Detail: "field is not filterable"}}
+	if err := s.Validate(o); !reflect.DeepEqual(err, want) {
+		t.Errorf("Schema.Validate() error = %v, want %v", err, want)
+	}
+}
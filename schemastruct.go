@@ -0,0 +1,117 @@
+package options
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SchemaFromStruct derives a Schema from the fields of the provided model
+// struct, where each field is named by its json tag (falling back to the
+// Go field name) and its schema tag lists what a client may do with it:
+//
+//	ID      int       `json:"id" schema:"filterable,sortable,ops=eq|in"`
+//	Name    string    `json:"name" schema:"filterable,sortable,ops=eq|like"`
+//	Created time.Time `json:"created" db:"created_at" schema:"sortable"`
+//	Secret  string    `json:"-"`
+//
+// Every field that is serialized is selectable unless tagged schema:"-".
+// The column of a field is provided via column= in the schema tag or the
+// db tag, and fields of embedded structs are included. The query tag is
+// left to Bind, so the same struct may be used with both.
+func SchemaFromStruct(model any) (Schema, error) {
+	typ := reflect.TypeOf(model)
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return Schema{}, fmt.Errorf("schema: model must be a struct, found %v", typ)
+	}
+
+	s := Schema{Fields: map[string]FieldSchema{}}
+	if err := addStructFields(s.Fields, typ); err != nil {
+		return Schema{}, err
+	}
+
+	return s, nil
+}
+
+func addStructFields(fields map[string]FieldSchema, typ reflect.Type) error {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		tag, tagged := sf.Tag.Lookup("schema")
+		if name == "-" || tag == "-" {
+			continue
+		}
+
+		// embedded structs without a json name are flattened
+		et := sf.Type
+		if et.Kind() == reflect.Pointer {
+			et = et.Elem()
+		}
+
+		if sf.Anonymous && name == "" && et.Kind() == reflect.Struct {
+			if err := addStructFields(fields, et); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		fs := FieldSchema{Selectable: true, Column: sf.Tag.Get("db")}
+		if tagged {
+			if err := parseSchemaTag(&fs, tag); err != nil {
+				return fmt.Errorf("schema: field %s: %w", sf.Name, err)
+			}
+		}
+
+		// the column is only required when it differs from the name
+		if fs.Column == name {
+			fs.Column = ""
+		}
+
+		fields[name] = fs
+	}
+
+	return nil
+}
+
+func parseSchemaTag(fs *FieldSchema, tag string) error {
+	for _, opt := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+
+		switch key {
+		case "":
+		case "filterable":
+			fs.Filterable = true
+		case "sortable":
+			fs.Sortable = true
+		case "column":
+			fs.Column = value
+		case "ops":
+			for _, name := range strings.Split(value, "|") {
+				op, err := ParseOperator(name)
+				if err != nil {
+					return err
+				}
+
+				fs.Operators = append(fs.Operators, op)
+			}
+		default:
+			return fmt.Errorf("unsupported schema tag option %q", key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaAudit struct {
+	Created time.Time `json:"created" db:"created_at" schema:"sortable,filterable,ops=gte|lte"`
+}
+
+type schemaPerson struct {
+	schemaAudit
+
+	ID       int    `json:"id" schema:"filterable,sortable,ops=eq|in"`
+	Name     string `json:"name,omitempty" schema:"filterable, sortable, ops=eq|like, column=full_name"`
+	Email    string `json:"email"`
+	Password string `json:"-" schema:"filterable"`
+	Internal string `json:"internal" schema:"-"`
+	Nickname string
+	private  string
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	got, err := SchemaFromStruct(&schemaPerson{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Schema{Fields: map[string]FieldSchema{
+		"created":  {Filterable: true, Sortable: true, Selectable: true, Operators: []Operator{OpGte, OpLte}, Column: "created_at"},
+		"id":       {Filterable: true, Sortable: true, Selectable: true, Operators: []Operator{OpEq, OpIn}},
+		"name":     {Filterable: true, Sortable: true, Selectable: true, Operators: []Operator{OpEq, OpLike}, Column: "full_name"},
+		"email":    {Selectable: true},
+		"Nickname": {Selectable: true},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SchemaFromStruct()\ngot:\n\t%+v\nwant:\n\t%+v", got, want)
+	}
+
+	wantColumns := map[string]string{"created": "created_at", "id": "id", "name": "full_name"}
+	if columns := got.Columns(); !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("Schema.Columns() = %v, want %v", columns, wantColumns)
+	}
+
+	if _, err := FromQuerystringWithSchema("filter[id]=1,2&filter[name][like]=A*&sort=-created&fields=email", got); err != nil {
+		t.Errorf("FromQuerystringWithSchema() error = %v", err)
+	}
+
+	if _, err := FromQuerystringWithSchema("filter[email]=a&filter[id][gt]=1&sort=Nickname", got); err == nil {
+		t.Error("FromQuerystringWithSchema() expected an error")
+	}
+}
+
+func TestSchemaFromStruct_bindStruct(t *testing.T) {
+	got, err := SchemaFromStruct(bindQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fs, ok := got.Fields["Limit"]; !ok || !reflect.DeepEqual(fs, FieldSchema{Selectable: true}) {
+		t.Errorf("SchemaFromStruct() Limit = %+v, want selectable", fs)
+	}
+}
+
+func TestSchemaFromStruct_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		model any
+	}{
+		{"nil", nil},
+		{"not a struct", 42},
+		{"unsupported option", struct {
+			A string `schema:"searchable"`
+		}{}},
+		{"unsupported operator", struct {
+			A string `schema:"filterable,ops=eq|about"`
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SchemaFromStruct(tt.model); err == nil {
+				t.Error("SchemaFromStruct() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IDialect is an interface for the database specific portions of
+// a generated SQL fragment
+type IDialect interface {
+	Placeholder(n int) string
+	QuoteIdentifier(name string) string
+}
+
+var (
+	// MySQL uses ? placeholders and backtick quoted identifiers
+	MySQL IDialect = mysql{}
+
+	// Postgres uses $n placeholders and double quoted identifiers
+	Postgres IDialect = postgres{}
+
+	// SQLite uses ? placeholders and double quoted identifiers
+	SQLite IDialect = sqlite{}
+)
+
+type mysql struct{}
+
+// Placeholder returns a ? placeholder
+func (mysql) Placeholder(n int) string {
+	return "?"
+}
+
+// QuoteIdentifier quotes each dot separated part of the name with backticks
+func (mysql) QuoteIdentifier(name string) string {
+	return quote(name, "`")
+}
+
+type postgres struct{}
+
+// Placeholder returns a $n placeholder
+func (postgres) Placeholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// QuoteIdentifier quotes each dot separated part of the name with double quotes
+func (postgres) QuoteIdentifier(name string) string {
+	return quote(name, `"`)
+}
+
+type sqlite struct{}
+
+// Placeholder returns a ? placeholder
+func (sqlite) Placeholder(n int) string {
+	return "?"
+}
+
+// QuoteIdentifier quotes each dot separated part of the name with double quotes
+func (sqlite) QuoteIdentifier(name string) string {
+	return quote(name, `"`)
+}
+
+// likeEscape returns the string literal of the \ escape character used
+// in LIKE patterns, MySQL treats \ as an escape within string literals
+func likeEscape(d IDialect) string {
+	if _, ok := d.(mysql); ok {
+		return `'\\'`
+	}
+
+	return `'\'`
+}
+
+func quote(name string, q string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		// escape any embedded quote characters by doubling them
+		parts[i] = q + strings.ReplaceAll(part, q, q+q) + q
+	}
+
+	return strings.Join(parts, ".")
+}
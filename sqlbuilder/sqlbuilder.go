@@ -0,0 +1,281 @@
+// sqlbuilder translates query Options into parameterized SQL
+// fragments (WHERE, ORDER BY and LIMIT/OFFSET) and bound arguments
+package sqlbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	options "go.jtlabs.io/query"
+)
+
+// likeEscaper escapes the wildcard characters of a LIKE pattern with \
+// and translates the * wildcard of the querystring
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", "%")
+
+// Builder creates SQL fragments from Options for a specific dialect
+type Builder struct {
+	// Columns maps the field names used in the querystring to database
+	// column names, any field that is not mapped is rejected
+	Columns map[string]string
+
+	// Dialect determines placeholder and identifier quoting syntax
+	Dialect IDialect
+
+	// PlaceholderOffset is the number of placeholders already used by
+	// the statement the generated fragment is appended to
+	PlaceholderOffset int
+}
+
+// Query is a parameterized SQL fragment along with its bound arguments
+type Query struct {
+	Where   string
+	OrderBy string
+	Limit   string
+	Args    []any
+}
+
+// String returns the WHERE, ORDER BY and LIMIT clauses of the Query
+// in the order SQL requires them
+func (q Query) String() string {
+	var clauses []string
+
+	if q.Where != "" {
+		clauses = append(clauses, "WHERE "+q.Where)
+	}
+
+	if q.OrderBy != "" {
+		clauses = append(clauses, "ORDER BY "+q.OrderBy)
+	}
+
+	if q.Limit != "" {
+		clauses = append(clauses, q.Limit)
+	}
+
+	return strings.Join(clauses, " ")
+}
+
+// New returns a Builder for the provided dialect and column mapping
+func New(d IDialect, columns map[string]string) Builder {
+	return Builder{
+		Columns: columns,
+		Dialect: d,
+	}
+}
+
+// Build translates the filters, sorting and pagination of the provided
+// Options into a Query
+func (b Builder) Build(o options.Options) (Query, error) {
+	var (
+		q   Query
+		err error
+	)
+
+	if q.Where, err = b.where(o, &q.Args); err != nil {
+		return Query{}, err
+	}
+
+	if q.OrderBy, err = b.orderBy(o); err != nil {
+		return Query{}, err
+	}
+
+	q.Limit = b.limit(o, &q.Args)
+
+	return q, nil
+}
+
+func (b Builder) column(field string) (string, error) {
+	col, ok := b.Columns[field]
+	if !ok {
+		return "", fmt.Errorf("sqlbuilder: field %q is not mapped to a column", field)
+	}
+
+	return b.dialect().QuoteIdentifier(col), nil
+}
+
+func (b Builder) dialect() IDialect {
+	if b.Dialect == nil {
+		return Postgres
+	}
+
+	return b.Dialect
+}
+
+func (b Builder) bind(args *[]any, value any) string {
+	*args = append(*args, value)
+	return b.dialect().Placeholder(b.PlaceholderOffset + len(*args))
+}
+
+func (b Builder) where(o options.Options, args *[]any) (string, error) {
+	var conditions []string
+
+	for _, fc := range o.Conditions() {
+		col, err := b.column(fc.Field)
+		if err != nil {
+			return "", err
+		}
+
+		cond, err := b.condition(col, fc, args)
+		if err != nil {
+			return "", err
+		}
+
+		conditions = append(conditions, cond)
+	}
+
+	// the filter expression must be satisfied along with the filters
+	if o.Expression != nil {
+		c := compiler{b: b, args: args}
+		if err := o.Expression.Accept(&c); err != nil {
+			return "", err
+		}
+
+		conditions = append(conditions, c.sql.String())
+	}
+
+	return strings.Join(conditions, " AND "), nil
+}
+
+// compiler is an options.Visitor that translates a filter expression
+// into a SQL condition, grouping each AND and OR in parentheses
+type compiler struct {
+	b    Builder
+	args *[]any
+	sql  strings.Builder
+}
+
+func (c *compiler) VisitAnd(e options.And) error {
+	return c.binary(e.Left, "AND", e.Right)
+}
+
+func (c *compiler) VisitOr(e options.Or) error {
+	return c.binary(e.Left, "OR", e.Right)
+}
+
+func (c *compiler) VisitNot(e options.Not) error {
+	c.sql.WriteString("NOT (")
+	if err := e.Expr.Accept(c); err != nil {
+		return err
+	}
+	c.sql.WriteString(")")
+
+	return nil
+}
+
+func (c *compiler) VisitCompare(e options.Compare) error {
+	col, err := c.b.column(e.Field)
+	if err != nil {
+		return err
+	}
+
+	cond, err := c.b.condition(col, e.Condition(), c.args)
+	if err != nil {
+		return err
+	}
+
+	c.sql.WriteString(cond)
+	return nil
+}
+
+func (c *compiler) binary(left options.Expr, op string, right options.Expr) error {
+	c.sql.WriteString("(")
+	if err := left.Accept(c); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(&c.sql, " %s ", op)
+	if err := right.Accept(c); err != nil {
+		return err
+	}
+	c.sql.WriteString(")")
+
+	return nil
+}
+
+func (b Builder) condition(col string, fc options.FilterCondition, args *[]any) (string, error) {
+	if len(fc.Values) == 0 || (fc.Operator == options.OpBetween && len(fc.Values) != 2) {
+		return "", fmt.Errorf("sqlbuilder: filter on %q has an invalid number of values", fc.Field)
+	}
+
+	switch fc.Operator {
+	case options.OpEq:
+		return fmt.Sprintf("%s = %s", col, b.bind(args, fc.Values[0])), nil
+	case options.OpNe:
+		return fmt.Sprintf("%s <> %s", col, b.bind(args, fc.Values[0])), nil
+	case options.OpLt:
+		return fmt.Sprintf("%s < %s", col, b.bind(args, fc.Values[0])), nil
+	case options.OpLte:
+		return fmt.Sprintf("%s <= %s", col, b.bind(args, fc.Values[0])), nil
+	case options.OpGt:
+		return fmt.Sprintf("%s > %s", col, b.bind(args, fc.Values[0])), nil
+	case options.OpGte:
+		return fmt.Sprintf("%s >= %s", col, b.bind(args, fc.Values[0])), nil
+	case options.OpIn, options.OpNin:
+		ph := make([]string, len(fc.Values))
+		for i, v := range fc.Values {
+			ph[i] = b.bind(args, v)
+		}
+
+		if fc.Operator == options.OpNin {
+			return fmt.Sprintf("%s NOT IN (%s)", col, strings.Join(ph, ", ")), nil
+		}
+
+		return fmt.Sprintf("%s IN (%s)", col, strings.Join(ph, ", ")), nil
+	case options.OpLike:
+		// * is accepted as a wildcard in the querystring, any other
+		// wildcard characters are matched literally
+		v := likeEscaper.Replace(fc.Values[0])
+		return fmt.Sprintf("%s LIKE %s ESCAPE %s", col, b.bind(args, v), likeEscape(b.dialect())), nil
+	case options.OpNull:
+		if fc.Values[0] == "false" {
+			return fmt.Sprintf("%s IS NOT NULL", col), nil
+		}
+
+		return fmt.Sprintf("%s IS NULL", col), nil
+	case options.OpBetween:
+		return fmt.Sprintf("%s BETWEEN %s AND %s", col, b.bind(args, fc.Values[0]), b.bind(args, fc.Values[1])), nil
+	default:
+		return "", fmt.Errorf("sqlbuilder: unsupported filter operator %q", fc.Operator)
+	}
+}
+
+func (b Builder) orderBy(o options.Options) (string, error) {
+	terms := make([]string, 0, len(o.Sort))
+
+	for _, field := range o.Sort {
+		dir := "ASC"
+		if strings.HasPrefix(field, "-") {
+			dir = "DESC"
+			field = field[1:]
+		} else if strings.HasPrefix(field, "+") {
+			field = field[1:]
+		}
+
+		col, err := b.column(field)
+		if err != nil {
+			return "", err
+		}
+
+		terms = append(terms, fmt.Sprintf("%s %s", col, dir))
+	}
+
+	return strings.Join(terms, ", "), nil
+}
+
+func (b Builder) limit(o options.Options, args *[]any) string {
+	// cursor pages are located by the caller's keyset condition
+	if _, ok := o.PaginationStrategy().(*options.CursorStrategy); ok {
+		if s, ok := o.Page["size"]; ok {
+			return fmt.Sprintf("LIMIT %s", b.bind(args, s))
+		}
+
+		return ""
+	}
+
+	l, offset, ok := o.LimitOffset()
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("LIMIT %s OFFSET %s", b.bind(args, l), b.bind(args, offset))
+}
@@ -0,0 +1,135 @@
+package sqlbuilder
+
+import (
+	"reflect"
+	"testing"
+
+	options "go.jtlabs.io/query"
+)
+
+func TestBuilder_Build(t *testing.T) {
+	columns := map[string]string{
+		"age":     "age",
+		"name":    "people.name",
+		"status":  "status",
+		"deleted": "deleted_at",
+		"created": "created_at",
+	}
+
+	tests := []struct {
+		name     string
+		dialect  IDialect
+		qs       string
+		want     string
+		wantArgs []any
+		wantErr  bool
+	}{
+		{"empty querystring", Postgres, "", "", nil, false},
+		{
+			"filters, sorting and offset pagination",
+			Postgres,
+			"filter[age][gte]=21&filter[status]=open,closed&sort=-age,name&page[limit]=10&page[offset]=20",
+			`WHERE "age" >= $1 AND "status" IN ($2, $3) ORDER BY "age" DESC, "people"."name" ASC LIMIT $4 OFFSET $5`,
+			[]any{"21", "open", "closed", 10, 20},
+			false,
+		},
+		{
+			"mysql dialect with page size pagination",
+			MySQL,
+			"filter[name][like]=jo*&filter[deleted][null]=true&page[size]=25&page[page]=2",
+			"WHERE `people`.`name` LIKE ? ESCAPE '\\\\' AND `deleted_at` IS NULL LIMIT ? OFFSET ?",
+			[]any{"jo%", 25, 50},
+			false,
+		},
+		{
+			"sqlite dialect with range and exclusion",
+			SQLite,
+			"filter[created][between]=2020-01-01,2020-12-31&filter[status][nin]=closed&filter[deleted][null]=false",
+			`WHERE "created_at" BETWEEN ? AND ? AND "status" NOT IN (?) AND "deleted_at" IS NOT NULL`,
+			[]any{"2020-01-01", "2020-12-31", "closed"},
+			false,
+		},
+		{
+			"like with literal wildcard characters",
+			Postgres,
+			"filter[name][like]=100%25_a%5C*",
+			`WHERE "people"."name" LIKE $1 ESCAPE '\'`,
+			[]any{`100\%\_a\\%`},
+			false,
+		},
+		{
+			"legacy comparison prefix",
+			Postgres,
+			"filter[age]=%21%3D30",
+			`WHERE "age" <> $1`,
+			[]any{"30"},
+			false,
+		},
+		{
+			"filter expression",
+			Postgres,
+			"filter[age][gte]=21&filter=(status eq 'open' or name like 'jo*') and not deleted null true",
+			`WHERE "age" >= $1 AND (("status" = $2 OR "people"."name" LIKE $3 ESCAPE '\') AND NOT ("deleted_at" IS NULL))`,
+			[]any{"21", "open", "jo%"},
+			false,
+		},
+		{
+			"cursor pagination",
+			Postgres,
+			"sort=-created&page[size]=10&page[after]=abc",
+			`ORDER BY "created_at" DESC LIMIT $1`,
+			[]any{10},
+			false,
+		},
+		{"unmapped filter field", Postgres, "filter[password]=test", "", nil, true},
+		{"unmapped filter expression field", Postgres, "filter=password eq 'test'", "", nil, true},
+		{"unmapped sort field", Postgres, "sort=name;DROP TABLE people", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := options.FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			q, err := New(tt.dialect, columns).Build(o)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.Build() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got := q.String(); got != tt.want {
+				t.Errorf("Builder.Build()\ngot:\n\t%s\nwant:\n\t%s", got, tt.want)
+			}
+
+			if !reflect.DeepEqual(q.Args, tt.wantArgs) {
+				t.Errorf("Builder.Build() args = %v, want %v", q.Args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuilder_PlaceholderOffset(t *testing.T) {
+	o, err := options.FromQuerystring("filter[age]=21")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := New(Postgres, map[string]string{"age": "age"})
+	b.PlaceholderOffset = 2
+
+	q, err := b.Build(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `"age" = $3`; q.Where != want {
+		t.Errorf("Builder.Build() where = %s, want %s", q.Where, want)
+	}
+}
+
+func Test_quote(t *testing.T) {
+	if got, want := Postgres.QuoteIdentifier(`bad"name`), `"bad""name"`; got != want {
+		t.Errorf("QuoteIdentifier() = %s, want %s", got, want)
+	}
+}
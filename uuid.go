@@ -0,0 +1,50 @@
+package options
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is a 128-bit universally unique identifier (see RFC 9562)
+type UUID [16]byte
+
+// ParseUUID reads a UUID in the canonical form (i.e.
+// 123e4567-e89b-12d3-a456-426614174000), with or without hyphens and
+// in either case
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, fmt.Errorf("invalid UUID %q", s)
+		}
+
+		s = s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return UUID{}, fmt.Errorf("invalid UUID %q", s)
+	}
+
+	return u, nil
+}
+
+// String returns the canonical, lowercase form of the UUID
+func (u UUID) String() string {
+	b := make([]byte, 36)
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+
+	return string(b)
+}
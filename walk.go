@@ -0,0 +1,108 @@
+package options
+
+// Walk traverses the expression depth-first, calling fn for each node
+// before its children, the children of a node are skipped when fn
+// returns false
+func Walk(e Expr, fn func(Expr) bool) {
+	if e == nil || !fn(e) {
+		return
+	}
+
+	switch e := e.(type) {
+	case And:
+		Walk(e.Left, fn)
+		Walk(e.Right, fn)
+	case Or:
+		Walk(e.Left, fn)
+		Walk(e.Right, fn)
+	case Not:
+		Walk(e.Expr, fn)
+	}
+}
+
+// Rewrite returns a copy of the expression in which each node is
+// replaced by the result of fn, which is called for each node after its
+// children have been rewritten
+//
+// When fn returns nil the node is removed: an And or Or with a single
+// remaining operand is replaced by that operand, and a Not without an
+// operand is removed.
+func Rewrite(e Expr, fn func(Expr) Expr) Expr {
+	switch n := e.(type) {
+	case nil:
+		return nil
+	case And:
+		left, right := Rewrite(n.Left, fn), Rewrite(n.Right, fn)
+		if left == nil || right == nil {
+			return rewriteOperand(left, right)
+		}
+
+		return fn(And{left, right})
+	case Or:
+		left, right := Rewrite(n.Left, fn), Rewrite(n.Right, fn)
+		if left == nil || right == nil {
+			return rewriteOperand(left, right)
+		}
+
+		return fn(Or{left, right})
+	case Not:
+		operand := Rewrite(n.Expr, fn)
+		if operand == nil {
+			return nil
+		}
+
+		return fn(Not{operand})
+	}
+
+	return fn(e)
+}
+
+// rewriteOperand returns the remaining operand of an And or Or when the
+// other has been removed
+func rewriteOperand(left Expr, right Expr) Expr {
+	if left != nil {
+		return left
+	}
+
+	return right
+}
+
+// FilterExpr returns a single expression combining the bracketed filters
+// (in the order of Conditions) and the filter expression with And, or nil
+// when no filters are provided
+func (o Options) FilterExpr() Expr {
+	var e Expr
+
+	for _, fc := range o.Conditions() {
+		e = andExpr(e, Compare(fc))
+	}
+
+	return andExpr(e, o.Expression)
+}
+
+func andExpr(left Expr, right Expr) Expr {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	}
+
+	return And{left, right}
+}
+
+// exprConditions returns the comparisons of the expression in the order
+// they appear
+func exprConditions(e Expr) []FilterCondition {
+	var fcs []FilterCondition
+
+	Walk(e, func(n Expr) bool {
+		if c, ok := n.(Compare); ok {
+			fcs = append(fcs, c.Condition())
+		}
+
+		return true
+	})
+
+	return fcs
+}
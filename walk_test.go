@@ -0,0 +1,175 @@
+package options
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	e, err := ParseExpression("(status eq open or priority gt 3) and not owner eq me")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	Walk(e, func(n Expr) bool {
+		switch n := n.(type) {
+		case And:
+			got = append(got, "and")
+		case Or:
+			got = append(got, "or")
+		case Not:
+			got = append(got, "not")
+
+			// skip the negated comparison
+			return false
+		case Compare:
+			got = append(got, n.Field)
+		}
+
+		return true
+	})
+
+	want := []string{"and", "or", "status", "priority", "not"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk() = %v, want %v", got, want)
+	}
+
+	Walk(nil, func(Expr) bool {
+		t.Error("Walk() called fn for a nil expression")
+		return true
+	})
+}
+
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		fn   func(Expr) Expr
+		want string
+	}{
+		{
+			"rename a field",
+			"status eq open or (state eq closed and not state eq new)",
+			func(e Expr) Expr {
+				if c, ok := e.(Compare); ok && c.Field == "state" {
+					c.Field = "status"
+					return c
+				}
+
+				return e
+			},
+			"status eq 'open' or status eq 'closed' and not status eq 'new'",
+		},
+		{
+			"remove comparisons",
+			"(status eq open or secret eq x) and not secret eq y and owner eq me",
+			func(e Expr) Expr {
+				if c, ok := e.(Compare); ok && c.Field == "secret" {
+					return nil
+				}
+
+				return e
+			},
+			"status eq 'open' and owner eq 'me'",
+		},
+		{
+			"replace an operator",
+			"not status ne open",
+			func(e Expr) Expr {
+				if n, ok := e.(Not); ok {
+					if c, ok := n.Expr.(Compare); ok && c.Operator == OpNe {
+						return Compare{c.Field, OpEq, c.Values}
+					}
+				}
+
+				return e
+			},
+			"status eq 'open'",
+		},
+		{
+			"remove everything",
+			"secret eq x or secret eq y",
+			func(e Expr) Expr {
+				if _, ok := e.(Compare); ok {
+					return nil
+				}
+
+				return e
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ParseExpression(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			original := e.String()
+
+			got := ""
+			if r := Rewrite(e, tt.fn); r != nil {
+				got = r.String()
+			}
+
+			if got != tt.want {
+				t.Errorf("Rewrite() = %q, want %q", got, tt.want)
+			}
+
+			if e.String() != original {
+				t.Errorf("Rewrite() modified the expression: %q", e.String())
+			}
+		})
+	}
+}
+
+func TestOptions_FilterExpr(t *testing.T) {
+	tests := []struct {
+		name string
+		qs   string
+		want Expr
+	}{
+		{"no filters", "sort=name", nil},
+		{
+			"bracketed filters",
+			"filter[b]=1,2&filter[a][gte]=3",
+			And{Compare{"b", OpIn, []string{"1", "2"}}, Compare{"a", OpGte, []string{"3"}}},
+		},
+		{
+			"legacy filters",
+			"filter[b]=1",
+			Compare{"b", OpEq, []string{"1"}},
+		},
+		{
+			"expression only",
+			"filter=a eq 1 or b eq 2",
+			Or{Compare{"a", OpEq, []string{"1"}}, Compare{"b", OpEq, []string{"2"}}},
+		},
+		{
+			"bracketed filters and expression",
+			"filter[c]=3&filter=a eq 1 or b eq 2",
+			And{Compare{"c", OpEq, []string{"3"}}, Or{Compare{"a", OpEq, []string{"1"}}, Compare{"b", OpEq, []string{"2"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := FromQuerystring(tt.qs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := o.FilterExpr(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Options.FilterExpr() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	// the legacy map is derived in field order
+	o := Options{Filter: map[string][]string{"b": {"2"}, "a": {"<1"}}}
+	want := And{Compare{"a", OpLt, []string{"1"}}, Compare{"b", OpEq, []string{"2"}}}
+	if got := o.FilterExpr(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Options.FilterExpr() = %#v, want %#v", got, want)
+	}
+}